@@ -60,35 +60,49 @@ func exec(line string, verbose bool) {
 }
 
 func main() {
-	fVendor := flag.String("vendor", string(vdriver.VendorValentina), "The vendor name, default 'Valentina'")
+	fVendor := flag.String("vendor", string(vdriver.VendorValentina), "The vendor name: Valentina, SQLite or DuckDB, default 'Valentina'")
 	fDB := flag.String("db", "", "The database name, required")
 	fUser := flag.String("u", "sa", "The user name, default 'sa'")
-	fPassword := flag.String("p", "sa", "The password, default 'sa'")
 	fHost := flag.String("host", "localhost", "The host name, default 'localhost'")
 	fPort := flag.Int("port", 0, "The port number, required")
 	fSSL := flag.Bool("ssl", false, "Use SSL")
 	fHelp := flag.Bool("h", false, "Print this help")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage of %s:\n", os.Args[0])
+		flag.PrintDefaults()
+		fmt.Fprintln(flag.CommandLine.Output(), "The password is read from VSQL_PASSWORD, or prompted for if it isn't set.")
+	}
 	flag.Parse()
 
 	if *fHelp || *fPort == 0 { // *fDB == "" ||
-		flag.PrintDefaults()
+		flag.Usage()
 		return
 	}
 
+	vendor, err := vdriver.ParseVendor(*fVendor)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	password, err := readPassword()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+
 	cfg := vdriver.Config{
 		DB:       *fDB,
 		User:     *fUser,
-		Password: *fPassword,
+		Password: password,
 		Host:     *fHost,
 		Port:     *fPort,
 		UseSSL:   *fSSL,
+		Vendor:   vendor,
 	}
 
-	var err error
-	db, err = sql.Open(*fVendor, cfg.FormatDSN())
-	if err != nil {
-		panic(err)
-	}
+	// The connector keeps the password out of any DSN
+	db = sql.OpenDB(vdriver.NewConnector(vendor, cfg))
 	defer func() {
 		if err := db.Close(); err != nil {
 			fmt.Fprintln(os.Stderr, "closing database:", err)
@@ -0,0 +1,47 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	osexec "os/exec"
+	"strings"
+)
+
+// readPassword returns $VSQL_PASSWORD, or prompts for the password on the terminal.
+func readPassword() (string, error) {
+	if password, ok := os.LookupEnv("VSQL_PASSWORD"); ok {
+		return password, nil
+	}
+
+	fi, err := os.Stdin.Stat()
+	if err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		return "", errors.New("standard input is not a terminal, set VSQL_PASSWORD")
+	}
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	// Hide the input where stty is available, the password is echoed otherwise
+	if err := stty("-echo"); err == nil {
+		defer func() {
+			stty("echo")
+			fmt.Fprintln(os.Stderr)
+		}()
+	}
+
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("reading password: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func stty(arg string) error {
+	cmd := osexec.Command("stty", arg)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
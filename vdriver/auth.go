@@ -0,0 +1,165 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vdriver
+
+import (
+	"bufio"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Authenticator provides the credentials of the REST sessions and authorizes the requests,
+// see WithAuthenticator and RegisterAuthenticator.
+type Authenticator interface {
+	// Credentials returns the user and password a session is created with. It is called for
+	// every login. An empty user or password selects the one of the Config.
+	Credentials(ctx context.Context) (user, password string, err error)
+
+	// Authorize is called for every request, including the login, i.e. to add headers
+	// for a reverse proxy in front of the REST port.
+	Authorize(req *http.Request) error
+}
+
+// MD5Auth returns the default authenticator: the password is sent as unsalted MD5 hash in
+// the body of the login request.
+func MD5Auth(user, password string) Authenticator {
+	return CredentialsFunc(func(context.Context) (string, string, error) {
+		return user, password, nil
+	})
+}
+
+// CredentialsFunc is an Authenticator that calls the function for every login, so the
+// credentials don't have to be part of the DSN, see EnvCredentials and FileCredentials.
+type CredentialsFunc func(ctx context.Context) (user, password string, err error)
+
+func (f CredentialsFunc) Credentials(ctx context.Context) (string, string, error) {
+	return f(ctx)
+}
+
+func (f CredentialsFunc) Authorize(req *http.Request) error {
+	return nil
+}
+
+// EnvCredentials reads the user and password from the environment variables of the given
+// names. An unset user variable selects the User of the Config.
+func EnvCredentials(userVar, passwordVar string) CredentialsFunc {
+	return func(context.Context) (string, string, error) {
+		password, ok := os.LookupEnv(passwordVar)
+		if !ok {
+			return "", "", fmt.Errorf("environment variable %s is not set", passwordVar)
+		}
+		return os.Getenv(userVar), password, nil
+	}
+}
+
+// FileCredentials reads the user and password from the first two lines of a file, i.e. a
+// mounted secret. The file is read again for every login, so the password can be rotated.
+func FileCredentials(path string) CredentialsFunc {
+	return func(context.Context) (string, string, error) {
+		f, err := os.Open(path)
+		if err != nil {
+			return "", "", fmt.Errorf("cannot read credentials: %w", err)
+		}
+		defer f.Close()
+
+		var lines []string
+		scanner := bufio.NewScanner(f)
+		for len(lines) < 2 && scanner.Scan() {
+			lines = append(lines, strings.TrimRight(scanner.Text(), "\r"))
+		}
+		if err := scanner.Err(); err != nil {
+			return "", "", fmt.Errorf("cannot read credentials: %w", err)
+		}
+		if len(lines) < 2 {
+			return "", "", fmt.Errorf("cannot read credentials: %s must contain the user and the password", path)
+		}
+		return lines[0], lines[1], nil
+	}
+}
+
+type headerAuth struct {
+	login         Authenticator
+	authorization string
+}
+
+// BasicAuth sends the user and password in an HTTP Basic Authorization header with every
+// request, i.e. for a reverse proxy in front of the REST port. The session is created with
+// the credentials of login, or those of the Config if login is nil.
+func BasicAuth(user, password string, login Authenticator) Authenticator {
+	req := http.Request{Header: make(http.Header)}
+	req.SetBasicAuth(user, password)
+	return headerAuth{login: login, authorization: req.Header.Get("Authorization")}
+}
+
+// BearerAuth sends the token in an HTTP Bearer Authorization header with every request.
+// The session is created with the credentials of login, or those of the Config if login is nil.
+func BearerAuth(token string, login Authenticator) Authenticator {
+	return headerAuth{login: login, authorization: "Bearer " + token}
+}
+
+func (a headerAuth) Credentials(ctx context.Context) (string, string, error) {
+	if a.login == nil {
+		return "", "", nil
+	}
+	return a.login.Credentials(ctx)
+}
+
+func (a headerAuth) Authorize(req *http.Request) error {
+	req.Header.Set("Authorization", a.authorization)
+	if a.login == nil {
+		return nil
+	}
+	return a.login.Authorize(req)
+}
+
+// hashPassword returns the password as the REST API expects it.
+func hashPassword(password string) string {
+	hash := md5.Sum([]byte(password))
+	return hex.EncodeToString(hash[:])
+}
+
+var (
+	authLock     sync.RWMutex
+	authRegistry = make(map[string]Authenticator)
+)
+
+// RegisterAuthenticator registers an authenticator, which can be used with the DSN parameter
+// auth=name. Use it to keep the password out of the DSN with sql.Open.
+//
+//	vdriver.RegisterAuthenticator("env", vdriver.EnvCredentials("VSQL_USER", "VSQL_PASSWORD"))
+//	db, err := sql.Open("valentina", "http://localhost:19998/testdb?auth=env")
+func RegisterAuthenticator(name string, auth Authenticator) error {
+	if name == "" {
+		return fmt.Errorf("authenticator name must not be empty")
+	}
+	if auth == nil {
+		return fmt.Errorf("authenticator %q is nil", name)
+	}
+
+	authLock.Lock()
+	defer authLock.Unlock()
+	authRegistry[name] = auth
+	return nil
+}
+
+// DeregisterAuthenticator removes an authenticator registered with RegisterAuthenticator.
+func DeregisterAuthenticator(name string) {
+	authLock.Lock()
+	defer authLock.Unlock()
+	delete(authRegistry, name)
+}
+
+func registeredAuthenticator(name string) (Authenticator, bool) {
+	authLock.RLock()
+	defer authLock.RUnlock()
+	auth, ok := authRegistry[name]
+	return auth, ok
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vdriver
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The REST API transfers BLOB and PICTURE values as hex encoded strings.
+
+// isBlobLiteral reports whether s may be a hex encoded BLOB.
+func isBlobLiteral(s string) bool {
+	if len(s)%2 != 0 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		ch := s[i]
+		if !(ch >= '0' && ch <= '9' || ch >= 'a' && ch <= 'f' || ch >= 'A' && ch <= 'F') {
+			return false
+		}
+	}
+	return true
+}
+
+// decodeBlob decodes a hex encoded BLOB value.
+func decodeBlob(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return []byte(s)
+	}
+	return b
+}
+
+// blobParam is an io.Reader parameter. It is hex encoded while the request is sent, so the
+// content is not held in memory, see vFastSQLRequest.writeJSON.
+type blobParam struct {
+	r     io.Reader
+	start int64 // Offset of a seekable reader
+	read  bool
+}
+
+func newBlobParam(r io.Reader) *blobParam {
+	b := &blobParam{r: r, start: -1}
+	if seeker, ok := r.(io.Seeker); ok {
+		if start, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+			b.start = start
+		}
+	}
+	return b
+}
+
+// writeHex writes the hex encoded content to w. Seekable readers are rewound, so the
+// parameter can be sent again if the query is replayed or retried by database/sql.
+func (b *blobParam) writeHex(w io.Writer) error {
+	if b.read && b.start < 0 {
+		return fmt.Errorf("cannot send BLOB parameter again: %T can't be rewound", b.r)
+	}
+	b.read = true
+
+	if _, err := io.Copy(hex.NewEncoder(w), b.r); err != nil {
+		return fmt.Errorf("cannot read BLOB parameter: %w", err)
+	}
+
+	if b.start >= 0 {
+		if _, err := b.r.(io.Seeker).Seek(b.start, io.SeekStart); err != nil {
+			return fmt.Errorf("cannot rewind BLOB parameter: %w", err)
+		}
+	}
+	return nil
+}
+
+// hexString returns the hex encoded content, i.e. for ARRAY literals.
+func (b *blobParam) hexString() (string, error) {
+	var sb strings.Builder
+	if err := b.writeHex(&sb); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+func (b *blobParam) String() string {
+	return fmt.Sprintf("BLOB(%T)", b.r)
+}
+
+// streamed reports whether the request has BLOB parameters that are streamed.
+func (msg vFastSQLRequest) streamed() bool {
+	for _, p := range msg.Params {
+		if _, ok := p.(*blobParam); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// writeJSON writes the request like json.Encoder, but hex encodes BLOB parameters while
+// they are written.
+func (msg vFastSQLRequest) writeJSON(w io.Writer) error {
+	params := msg.Params
+	msg.Params = nil
+	head, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	bw.Write(head[:len(head)-1]) // Without the closing brace
+	bw.WriteString(`,"Params":[`)
+	for i, p := range params {
+		if i > 0 {
+			bw.WriteByte(',')
+		}
+		if blob, ok := p.(*blobParam); ok {
+			bw.WriteByte('"')
+			if err := blob.writeHex(bw); err != nil {
+				return err
+			}
+			bw.WriteByte('"')
+			continue
+		}
+
+		value, err := json.Marshal(p)
+		if err != nil {
+			return err
+		}
+		bw.Write(value)
+	}
+	bw.WriteString("]}\n")
+	return bw.Flush()
+}
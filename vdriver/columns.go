@@ -0,0 +1,427 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vdriver
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The REST API only returns the names of the result fields. Type information is looked up
+// from the source table of simple queries and cached per connection.
+
+type typeKind int
+
+const (
+	kindUnknown typeKind = iota
+	kindBool
+	kindInt
+	kindFloat
+	kindDecimal
+	kindString
+	kindDate
+	kindTime
+	kindDateTime
+	kindBinary
+	kindArray
+)
+
+var typeKinds = map[string]typeKind{
+	"BOOLEAN": kindBool, "BOOL": kindBool,
+
+	"BYTE": kindInt, "SHORT": kindInt, "USHORT": kindInt, "MEDIUM": kindInt, "UMEDIUM": kindInt,
+	"LONG": kindInt, "ULONG": kindInt, "LLONG": kindInt, "ULLONG": kindInt,
+	"TINYINT": kindInt, "SMALLINT": kindInt, "INT": kindInt, "INTEGER": kindInt, "BIGINT": kindInt,
+	"UTINYINT": kindInt, "USMALLINT": kindInt, "UINTEGER": kindInt, "UBIGINT": kindInt,
+	"INT1": kindInt, "INT2": kindInt, "INT4": kindInt, "INT8": kindInt,
+
+	"FLOAT": kindFloat, "DOUBLE": kindFloat, "REAL": kindFloat, "FLOAT4": kindFloat, "FLOAT8": kindFloat,
+
+	"DECIMAL": kindDecimal, "NUMERIC": kindDecimal, "MONEY": kindDecimal, "CURRENCY": kindDecimal,
+	"HUGEINT": kindDecimal, "UHUGEINT": kindDecimal,
+
+	"STRING": kindString, "VARCHAR": kindString, "CHAR": kindString, "TEXT": kindString,
+	"FIXEDSTRING": kindString, "VARCHARSTRING": kindString, "UUID": kindString, "JSON": kindString,
+
+	"DATE": kindDate, "TIME": kindTime,
+	"DATETIME": kindDateTime, "TIMESTAMP": kindDateTime, "TIMESTAMPTZ": kindDateTime,
+	"TIMESTAMP WITH TIME ZONE": kindDateTime,
+
+	"BLOB": kindBinary, "PICTURE": kindBinary, "BYTEA": kindBinary, "BINARY": kindBinary,
+	"VARBINARY": kindBinary, "FIXEDBINARY": kindBinary,
+
+	"ARRAY": kindArray,
+}
+
+var (
+	scanTypeAny     = reflect.TypeFor[any]()
+	scanTypeBool    = reflect.TypeFor[bool]()
+	scanTypeInt64   = reflect.TypeFor[int64]()
+	scanTypeFloat64 = reflect.TypeFor[float64]()
+	scanTypeString  = reflect.TypeFor[string]()
+	scanTypeBytes   = reflect.TypeFor[[]byte]()
+	scanTypeArray   = reflect.TypeFor[[]any]()
+	scanTypeTime    = reflect.TypeFor[time.Time]()
+)
+
+// columnType describes a result field. The zero value is an unknown type.
+type columnType struct {
+	name        string
+	typeName    string // Upper case type name without arguments, i.e. VARCHAR
+	kind        typeKind
+	nullable    bool
+	hasNullable bool
+	length      int64
+	precision   int64
+	scale       int64
+}
+
+func (ct columnType) scanType() reflect.Type {
+	switch ct.kind {
+	case kindBool:
+		return scanTypeBool
+	case kindInt:
+		return scanTypeInt64
+	case kindFloat:
+		return scanTypeFloat64
+	case kindDecimal, kindString, kindDate, kindTime, kindDateTime:
+		return scanTypeString
+	case kindBinary:
+		return scanTypeBytes
+	case kindArray:
+		return scanTypeArray
+	}
+	return scanTypeAny
+}
+
+// parseColumnType parses a declared type like "VARCHAR(20)" or "DECIMAL(10,2)".
+func parseColumnType(name, decl string) columnType {
+	ct := columnType{name: name}
+
+	decl = strings.ToUpper(strings.TrimSpace(decl))
+	var args []int64
+	if open := strings.IndexByte(decl, '('); open >= 0 && strings.HasSuffix(decl, ")") {
+		for _, arg := range strings.Split(decl[open+1:len(decl)-1], ",") {
+			n, err := strconv.ParseInt(strings.TrimSpace(arg), 10, 64)
+			if err != nil {
+				break
+			}
+			args = append(args, n)
+		}
+		decl = strings.TrimSpace(decl[:open])
+	}
+	ct.typeName = decl
+
+	ct.kind = typeKindOf(decl)
+	switch ct.kind {
+	case kindString, kindBinary:
+		if len(args) > 0 {
+			ct.length = args[0]
+		}
+	case kindDecimal:
+		if len(args) > 0 {
+			ct.precision = args[0]
+		}
+		if len(args) > 1 {
+			ct.scale = args[1]
+		}
+	}
+
+	return ct
+}
+
+func typeKindOf(typeName string) typeKind {
+	if kind, ok := typeKinds[typeName]; ok {
+		return kind
+	}
+	if strings.HasSuffix(typeName, "[]") {
+		return kindArray
+	}
+
+	// SQLite accepts any type name, so we fall back to its affinity rules
+	switch {
+	case strings.Contains(typeName, "INT"):
+		return kindInt
+	case strings.Contains(typeName, "CHAR"), strings.Contains(typeName, "CLOB"), strings.Contains(typeName, "TEXT"):
+		return kindString
+	case strings.Contains(typeName, "BLOB"):
+		return kindBinary
+	case strings.Contains(typeName, "REAL"), strings.Contains(typeName, "FLOA"), strings.Contains(typeName, "DOUB"):
+		return kindFloat
+	}
+	return kindUnknown
+}
+
+var reSourceTable = regexp.MustCompile(`(?is)^\s*SELECT\s+(.*?)\s+FROM\s+(` + identPattern + `)\s*(?:(?:WHERE|ORDER|GROUP|HAVING|LIMIT)\s.*)?;?\s*$`)
+
+const identPattern = `\w+|"[^"]+"|\[[^\]]+\]|` + "`[^`]+`"
+
+// reColumnRef matches a bare column reference like name, t.name or "t"."name", or a star.
+var reColumnRef = regexp.MustCompile(`^(?:(?:` + identPattern + `)\.)?(` + identPattern + `|\*)$`)
+
+// sourceTable returns the table a simple single-table SELECT reads from and the items of its
+// select list, or "" for other queries.
+func sourceTable(query string) (table string, items []string) {
+	m := reSourceTable.FindStringSubmatch(query)
+	if m == nil {
+		return "", nil
+	}
+	return m[2], splitSelectList(m[1])
+}
+
+// splitSelectList splits a select list at the commas outside of parentheses and quotes.
+func splitSelectList(list string) []string {
+	var items []string
+	depth, start := 0, 0
+	for i := 0; i < len(list); i++ {
+		switch ch := list[i]; ch {
+		case '\'', '"', '`':
+			i = skipQuoted(list, i, ch) - 1
+		case '[':
+			i = skipQuoted(list, i, ']') - 1
+		case '(':
+			depth++
+		case ')':
+			depth--
+		case ',':
+			if depth == 0 {
+				items = append(items, strings.TrimSpace(list[start:i]))
+				start = i + 1
+			}
+		}
+	}
+	return append(items, strings.TrimSpace(list[start:]))
+}
+
+// unquoteIdent removes the quotes or brackets around an identifier.
+func unquoteIdent(ident string) string {
+	if len(ident) >= 2 {
+		switch ident[0] {
+		case '"', '`':
+			if ident[len(ident)-1] == ident[0] {
+				return ident[1 : len(ident)-1]
+			}
+		case '[':
+			if ident[len(ident)-1] == ']' {
+				return ident[1 : len(ident)-1]
+			}
+		}
+	}
+	return ident
+}
+
+// columnTypesTTL limits how long the column types of a table are cached, so changes by
+// other connections are picked up.
+const columnTypesTTL = time.Minute
+
+type cachedColumns struct {
+	cols    []columnType
+	expires time.Time
+}
+
+// tableColumns returns the column types of a table. Results are cached per connection,
+// including failed lookups, which return nil.
+func (c *vConn) tableColumns(ctx context.Context, table string) []columnType {
+	key := strings.ToLower(unquoteIdent(table))
+	if cached, ok := c.columnTypes[key]; ok && time.Now().Before(cached.expires) {
+		return cached.cols
+	}
+
+	cols, err := c.lookupTableColumns(ctx, table)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil // Try again next time
+		}
+		cols = nil
+	}
+
+	if c.columnTypes == nil {
+		c.columnTypes = make(map[string]cachedColumns)
+	}
+	c.columnTypes[key] = cachedColumns{cols: cols, expires: time.Now().Add(columnTypesTTL)}
+	return cols
+}
+
+// fieldTypes returns the types of the result fields of a table, nil if they are unknown.
+// Only bare column references of the select list get the type of their column. Expressions
+// stay unknown, even if their alias is the name of a column.
+func fieldTypes(fields, items []string, cols []columnType) []columnType {
+	if cols == nil {
+		return nil
+	}
+
+	// The column each field refers to, "" for expressions
+	refs := make([]string, len(fields))
+	star := false
+	for _, item := range items {
+		if m := reColumnRef.FindStringSubmatch(item); m != nil && m[1] == "*" {
+			star = true
+		}
+	}
+	switch {
+	case star:
+		// A star expands into an unknown number of fields, so they can only be matched by name
+		for _, item := range items {
+			if !reColumnRef.MatchString(item) {
+				return nil
+			}
+		}
+		copy(refs, fields)
+	case len(items) == len(fields):
+		for i, item := range items {
+			if m := reColumnRef.FindStringSubmatch(item); m != nil {
+				refs[i] = unquoteIdent(m[1])
+			}
+		}
+	default:
+		return nil
+	}
+
+	types := make([]columnType, len(fields))
+	for i, name := range refs {
+		if name == "" {
+			continue
+		}
+		for _, col := range cols {
+			if strings.EqualFold(col.name, name) {
+				types[i] = col
+				break
+			}
+		}
+	}
+	return types
+}
+
+// invalidateColumns drops the cached column types if the query may change a table.
+func (c *vConn) invalidateColumns(query string) {
+	if len(c.columnTypes) > 0 && isDDL(query, Vendor(c.vendor)) {
+		c.columnTypes = nil
+	}
+}
+
+// isDDL reports whether a statement creates, alters or drops a schema object.
+func isDDL(query string, vendor Vendor) bool {
+	l := newLexer(query, vendor)
+	for {
+		tok, ok := l.next()
+		if !ok {
+			return false
+		}
+		if tok.kind == tokComment {
+			continue
+		}
+		fields := strings.Fields(tok.text)
+		if len(fields) == 0 {
+			continue
+		}
+		switch strings.ToUpper(fields[0]) {
+		case "CREATE", "ALTER", "DROP", "RENAME":
+			return true
+		}
+		return false
+	}
+}
+
+func (c *vConn) lookupTableColumns(ctx context.Context, table string) ([]columnType, error) {
+	var query string
+	switch Vendor(c.vendor) {
+	case VendorSQLite:
+		query = "PRAGMA table_info(" + table + ")"
+	case VendorDuckDB:
+		query = "DESCRIBE " + table
+	default:
+		query = "SHOW COLUMNS FROM " + table
+	}
+
+	rows, err := c.QueryContext(ctx, query, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fields := make(map[string]int)
+	for i, name := range rows.Columns() {
+		fields[strings.ToLower(name)] = i
+	}
+	field := func(values []driver.Value, names ...string) (driver.Value, bool) {
+		for _, name := range names {
+			if i, ok := fields[name]; ok {
+				return values[i], true
+			}
+		}
+		return nil, false
+	}
+
+	var cols []columnType
+	values := make([]driver.Value, len(fields))
+	for {
+		err := rows.Next(values)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		name, _ := field(values, "fld_name", "name", "column_name")
+		decl, _ := field(values, "fld_type_str", "fld_type", "type", "column_type")
+		ct := parseColumnType(fmt.Sprint(name), fmt.Sprint(decl))
+
+		// Valentina, SQLite and DuckDB each report nullability differently
+		if v, ok := field(values, "fld_nullable", "fld_is_nullable", "null"); ok {
+			ct.nullable, ct.hasNullable = truthy(v), true
+		} else if v, ok := field(values, "notnull"); ok {
+			ct.nullable, ct.hasNullable = !truthy(v), true
+		}
+		if v, ok := field(values, "fld_max_length", "fld_length"); ok && ct.length == 0 {
+			ct.length = toInt64(v)
+		}
+		if v, ok := field(values, "fld_precision"); ok && ct.precision == 0 {
+			ct.precision = toInt64(v)
+		}
+		if v, ok := field(values, "fld_scale"); ok && ct.scale == 0 {
+			ct.scale = toInt64(v)
+		}
+
+		cols = append(cols, ct)
+	}
+
+	return cols, nil
+}
+
+func truthy(v driver.Value) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case string:
+		switch strings.ToUpper(v) {
+		case "1", "YES", "TRUE", "Y":
+			return true
+		}
+	default:
+		return toInt64(v) != 0
+	}
+	return false
+}
+
+func toInt64(v driver.Value) int64 {
+	switch v := v.(type) {
+	case int64:
+		return v
+	case float64:
+		return int64(v)
+	case string:
+		n, _ := strconv.ParseInt(v, 10, 64)
+		return n
+	}
+	return 0
+}
@@ -5,8 +5,14 @@
 package vdriver
 
 import (
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net"
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type Vendor string
@@ -17,6 +23,13 @@ const (
 	VendorDuckDB    Vendor = "DuckDB"
 )
 
+// Default ports of the REST API, see PORT_REST and PORT_REST_SSL in vserver.ini
+const (
+	defaultPort    = 19998
+	defaultPortSSL = 19999
+)
+
+// Config is the connection configuration, see ParseDSN for the DSN format.
 type Config struct {
 	DB       string
 	User     string
@@ -24,24 +37,325 @@ type Config struct {
 	Host     string
 	Port     int
 	UseSSL   bool
+
+	// Vendor overrides the vendor of the driver or connector, if set.
+	Vendor Vendor
+
+	// Timeout limits establishing the TCP connection and the TLS handshake, 0 means no timeout.
+	Timeout time.Duration
+
+	// ReadTimeout limits waiting for the response headers of a request, 0 means no timeout.
+	ReadTimeout time.Duration
+
+	// QueryTimeout is applied to queries whose context has no deadline, 0 means no timeout.
+	QueryTimeout time.Duration
+
+	// TLS selects the TLS configuration if UseSSL is set: "skip-verify" disables the
+	// certificate verification, other names refer to configurations registered with
+	// RegisterTLSConfig. Empty uses the system's default verification.
+	TLS string
+
+	// TLSConfig is used instead of TLS if set, and implies UseSSL. It can't be part of a DSN.
+	TLSConfig *tls.Config
+
+	// Logger is the name of a logger registered with RegisterLogger, or "default" for
+	// slog.Default(). Empty disables logging, see WithLogger.
+	Logger string
+
+	// LogParams logs the values of query parameters, which are redacted by default.
+	LogParams bool
+
+	// Auth is the name of an authenticator registered with RegisterAuthenticator. Empty sends
+	// User and Password with the default MD5 scheme, see MD5Auth.
+	Auth string
+
+	// DateFormat is the DateTimeFormat of the session: kYMD (default), kDMY or kMDY.
+	DateFormat string
+
+	// MaxResponseBytes limits the size of a response body, 0 means no limit.
+	MaxResponseBytes int64
+
+	// ApplicationName is sent in the User-Agent header.
+	ApplicationName string
+
+	// ParseTime returns DATE, TIME and DATETIME values as time.Time instead of strings.
+	ParseTime bool
+
+	// SessionTimeout is the idle time after which the server removes a REST session, see
+	// MAXIDLECLIENTTIMEOUT in vserver.ini. Idle connections log in again before it passes.
+	// 0 means 20 minutes.
+	SessionTimeout time.Duration
+
+	// Relogin logs in again when a request fails because the server removed the session,
+	// keeping the connection usable. Read-only statements (SELECT, SHOW, GET PROPERTY) are
+	// executed again, other statements fail with ErrSessionExpired. SELECT … INTO and other
+	// SELECTs that write or lock records are not read-only.
+	Relogin bool
+
+	// AutoLastInsertID looks up the ID of the inserted record after each INSERT on the same
+	// session, so Result.LastInsertId works. It costs a round trip except on DuckDB.
+	AutoLastInsertID bool
+
+	// Loc is the time zone of the server's date and time values, time.Time parameters are
+	// converted into it. Defaults to UTC. It requires ParseTime, otherwise scanned strings
+	// would be read in another location than parameters are written.
+	Loc *time.Location
+}
+
+// ParseDSN parses a DSN of the form
+//
+//	http[s]://user:password@host[:port]/[database][?param=value&...]
+//
+// Supported parameters are vendor, timeout, readTimeout, queryTimeout, tls (true, false,
+// skip-verify or a name registered with RegisterTLSConfig), dateFormat, maxResponseBytes,
+// applicationName, parseTime, loc (a time zone name like UTC, Local or Europe/Berlin),
+// lastInsertId (auto or off), sessionTimeout, relogin, auth (a name registered with
+// RegisterAuthenticator), logger (default or a name registered with RegisterLogger) and
+// logParams. The user and password are optional with auth.
+// Unknown parameters are rejected.
+func ParseDSN(dsn string) (Config, error) {
+	var cfg Config
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		// The *url.Error contains the DSN including the password
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return cfg, fmt.Errorf("invalid DSN: %w", err)
+	}
+
+	switch u.Scheme {
+	case "http":
+	case "https":
+		cfg.UseSSL = true
+	default:
+		return cfg, fmt.Errorf("invalid DSN: unsupported scheme %q", u.Scheme)
+	}
+
+	if u.User != nil {
+		cfg.User = u.User.Username()
+		cfg.Password, _ = u.User.Password()
+	}
+
+	cfg.Host = u.Hostname()
+	if port := u.Port(); port != "" {
+		cfg.Port, err = strconv.Atoi(port)
+		if err != nil {
+			return cfg, fmt.Errorf("invalid DSN: invalid port %q", port)
+		}
+	}
+	cfg.DB = strings.TrimPrefix(u.Path, "/")
+
+	for name, values := range u.Query() {
+		value := values[len(values)-1]
+
+		switch name {
+		case "vendor":
+			cfg.Vendor, err = ParseVendor(value)
+		case "timeout":
+			cfg.Timeout, err = time.ParseDuration(value)
+		case "readTimeout":
+			cfg.ReadTimeout, err = time.ParseDuration(value)
+		case "queryTimeout":
+			cfg.QueryTimeout, err = time.ParseDuration(value)
+		case "tls":
+			switch value {
+			case "true":
+				cfg.UseSSL = true
+			case "false":
+				if u.Scheme == "https" {
+					err = fmt.Errorf("conflicts with https")
+				}
+			default:
+				cfg.UseSSL = true
+				cfg.TLS = value
+			}
+		case "dateFormat":
+			switch value {
+			case "kYMD", "kDMY", "kMDY":
+				cfg.DateFormat = value
+			default:
+				err = fmt.Errorf("must be kYMD, kDMY or kMDY")
+			}
+		case "maxResponseBytes":
+			cfg.MaxResponseBytes, err = strconv.ParseInt(value, 10, 64)
+			if err == nil && cfg.MaxResponseBytes < 0 {
+				err = fmt.Errorf("must not be negative")
+			}
+		case "applicationName":
+			cfg.ApplicationName = value
+		case "parseTime":
+			cfg.ParseTime, err = strconv.ParseBool(value)
+		case "loc":
+			cfg.Loc, err = time.LoadLocation(value)
+		case "sessionTimeout":
+			cfg.SessionTimeout, err = time.ParseDuration(value)
+			if err == nil && cfg.SessionTimeout < 0 {
+				err = fmt.Errorf("must not be negative")
+			}
+		case "relogin":
+			cfg.Relogin, err = strconv.ParseBool(value)
+		case "auth":
+			cfg.Auth = value
+		case "logger":
+			cfg.Logger = value
+		case "logParams":
+			cfg.LogParams, err = strconv.ParseBool(value)
+		case "lastInsertId":
+			switch value {
+			case "auto":
+				cfg.AutoLastInsertID = true
+			case "off":
+				cfg.AutoLastInsertID = false
+			default:
+				err = fmt.Errorf("must be auto or off")
+			}
+		default:
+			return cfg, fmt.Errorf("invalid DSN: unknown parameter %q", name)
+		}
+
+		if err != nil {
+			return cfg, fmt.Errorf("invalid DSN: invalid value for %s: %w", name, err)
+		}
+	}
+
+	if err := cfg.validate(); err != nil {
+		return cfg, fmt.Errorf("invalid DSN: %w", err)
+	}
+	return cfg, nil
+}
+
+// validate checks the combination of options.
+func (cfg Config) validate() error {
+	if cfg.Loc != nil && !cfg.ParseTime {
+		return fmt.Errorf("loc requires parseTime=true")
+	}
+	return nil
 }
 
+// ParseVendor returns the vendor of a name like Valentina, SQLite or DuckDB, ignoring case.
+func ParseVendor(value string) (Vendor, error) {
+	for _, vendor := range []Vendor{VendorValentina, VendorSQLite, VendorDuckDB} {
+		if strings.EqualFold(value, string(vendor)) {
+			return vendor, nil
+		}
+	}
+	return "", fmt.Errorf("unknown vendor %q", value)
+}
+
+// FormatDSN returns the DSN for the configuration, ParseDSN returns the same configuration for it.
 func (cfg Config) FormatDSN() string {
 	return cfg.makeURL().String()
 }
 
+// RedactedDSN returns the DSN with the password replaced by xxxxx, for logs and error messages.
+func (cfg Config) RedactedDSN() string {
+	return cfg.makeURL().Redacted()
+}
+
+// String returns the redacted DSN, so printing a configuration doesn't reveal the password.
+func (cfg Config) String() string {
+	return cfg.RedactedDSN()
+}
+
 func (cfg Config) makeURL() *url.URL {
 	scheme := "http"
-	if cfg.UseSSL {
+	if cfg.useTLS() {
 		scheme = "https"
 	}
 
+	host := cfg.Host
+	if cfg.Port != 0 {
+		host = net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]" // IPv6 address without port
+	}
+
 	connURL := url.URL{
 		Scheme: scheme,
-		User:   url.UserPassword(cfg.User, cfg.Password),
-		Host:   fmt.Sprintf("%s:%d", cfg.Host, cfg.Port),
+		Host:   host,
 		Path:   "/" + cfg.DB,
 	}
+	if cfg.Password != "" {
+		connURL.User = url.UserPassword(cfg.User, cfg.Password)
+	} else if cfg.User != "" {
+		connURL.User = url.User(cfg.User)
+	}
+
+	params := url.Values{}
+	if cfg.Vendor != "" {
+		params.Set("vendor", string(cfg.Vendor))
+	}
+	if cfg.Timeout > 0 {
+		params.Set("timeout", cfg.Timeout.String())
+	}
+	if cfg.ReadTimeout > 0 {
+		params.Set("readTimeout", cfg.ReadTimeout.String())
+	}
+	if cfg.QueryTimeout > 0 {
+		params.Set("queryTimeout", cfg.QueryTimeout.String())
+	}
+	if cfg.TLS != "" {
+		params.Set("tls", cfg.TLS)
+	}
+	if cfg.DateFormat != "" {
+		params.Set("dateFormat", cfg.DateFormat)
+	}
+	if cfg.MaxResponseBytes > 0 {
+		params.Set("maxResponseBytes", strconv.FormatInt(cfg.MaxResponseBytes, 10))
+	}
+	if cfg.ApplicationName != "" {
+		params.Set("applicationName", cfg.ApplicationName)
+	}
+	if cfg.ParseTime {
+		params.Set("parseTime", "true")
+	}
+	if cfg.Loc != nil {
+		params.Set("loc", cfg.Loc.String())
+	}
+	if cfg.SessionTimeout > 0 {
+		params.Set("sessionTimeout", cfg.SessionTimeout.String())
+	}
+	if cfg.Relogin {
+		params.Set("relogin", "true")
+	}
+	if cfg.AutoLastInsertID {
+		params.Set("lastInsertId", "auto")
+	}
+	if cfg.Auth != "" {
+		params.Set("auth", cfg.Auth)
+	}
+	if cfg.Logger != "" {
+		params.Set("logger", cfg.Logger)
+	}
+	if cfg.LogParams {
+		params.Set("logParams", "true")
+	}
+	connURL.RawQuery = params.Encode()
 
 	return &connURL
 }
+
+// endpoint returns the base URL of the REST API, i.e. http://localhost:19998
+func (cfg Config) endpoint() string {
+	port := cfg.Port
+	if port == 0 {
+		port = defaultPort
+		if cfg.useTLS() {
+			port = defaultPortSSL
+		}
+	}
+
+	scheme := "http"
+	if cfg.useTLS() {
+		scheme = "https"
+	}
+
+	return scheme + "://" + net.JoinHostPort(cfg.Host, strconv.Itoa(port))
+}
+
+func (cfg Config) useTLS() bool {
+	return cfg.UseSSL || cfg.TLSConfig != nil
+}
@@ -7,64 +7,101 @@ package vdriver
 import (
 	"bytes"
 	"context"
-	"crypto/md5"
 	"database/sql/driver"
-	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
-	"net/url"
 	"strings"
+	"time"
 )
 
+// closeTimeout limits how long Close waits for the server to remove the session.
+const closeTimeout = 10 * time.Second
+
 type vConn struct {
 	httpClient *http.Client
-	restURL    *url.URL
+	endpoint   string // Base URL of the REST API
+	user       string
+	password   string
 	sessionID  string
 	database   string
 	vendor     string
+	dateFormat string // DateTimeFormat of the session, see dateLayout
+
+	queryTimeout     time.Duration // Default timeout for queries without a deadline
+	parseTime        bool
+	autoLastInsertID bool           // Look up the ID after INSERTs, see lastInsertID
+	loc              *time.Location // Time zone of the server's values, nil if not configured
+	maxResponseBytes int64
+	userAgent        string
+	header           http.Header   // Additional headers, see WithHeader
+	auth             Authenticator // Provides the credentials and authorizes requests
+	logger           *slog.Logger  // Receives the events of the connection, nil disables logging
+	logParams        bool          // Log the values of parameters, they are redacted otherwise
+	bad              bool          // Set if a request was canceled, the session must not be reused
+
+	columnTypes map[string]cachedColumns // Cached column types per table, see tableColumns
+	streaming   *vRows                   // Open rows whose records are streamed from their response
+
+	created   time.Time // See SessionInfo
+	lastUsed  time.Time
+	refreshes int
+	expired   bool // The server reported that the session doesn't exist anymore
+
+	sessionTimeout time.Duration // Idle time after which the server removes the session
+	reloginEnabled bool          // Log in again when a request fails with an expired session
+	inTx           bool          // A transaction is open, its session must not be replaced
 }
 
 func (c *vConn) Prepare(query string) (driver.Stmt, error) {
 	return &vStmt{
-		query: query,
-		conn:  c,
+		query:    query,
+		conn:     c,
+		numInput: numInput(query, Vendor(c.vendor)),
 	}, nil
 }
 
 // Close removes the REST session from the server
 func (c *vConn) Close() error {
-	ctx := context.Background()
+	ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+
+	return c.deleteSession(ctx)
+}
+
+func (c *vConn) deleteSession(ctx context.Context) error {
 	resp, err := c.makeRequest(ctx, http.MethodDelete, "/rest/session_id", nil)
 	if err != nil {
 		return fmt.Errorf("makeRequest failed: %w", err)
 	}
+	defer resp.Body.Close()
 
+	// A session the server already removed is closed, too
 	if resp.StatusCode != http.StatusNoContent {
-		// Check if it has a body
-		if resp.ContentLength == 0 {
-			return fmt.Errorf("unexpected status code: %v", resp.StatusCode)
-		}
-		body, err := io.ReadAll(resp.Body)
-		resp.Body.Close()
-		if err == nil {
-			return fmt.Errorf("error: %s", string(body))
+		if err := c.readError(resp, ""); !IsSessionExpired(err) {
+			return err
 		}
+	} else if c.sessionID != "" && !c.expired {
+		stats.sessionsClosed.Add(1)
+		c.log(ctx, slog.LevelDebug, "valentina session closed", slog.String("endpoint", c.endpoint), slog.Duration("age", time.Since(c.created)))
 	}
 
 	c.sessionID = ""
-	c.httpClient.CloseIdleConnections()
 	return nil
 }
 
+// Begin starts a transaction with default options, see BeginTx.
 func (c *vConn) Begin() (driver.Tx, error) {
-	return vTx{
-		conn: c,
-	}, nil
+	return c.BeginTx(context.Background(), driver.TxOptions{})
 }
 
 func (c *vConn) Ping(ctx context.Context) error {
+	if c.bad {
+		return driver.ErrBadConn
+	}
+
 	rows, err := c.QueryContext(ctx, "SELECT version()", nil)
 	if err != nil {
 		return err
@@ -89,8 +126,8 @@ func (c *vConn) Ping(ctx context.Context) error {
 	return nil
 }
 
-func (c *vConn) getDatabasePropertyString(name string) (string, error) {
-	rows, err := c.QueryContext(context.Background(), "GET PROPERTY "+name+" OF DATABASE", nil)
+func (c *vConn) getDatabasePropertyString(ctx context.Context, name string) (string, error) {
+	rows, err := c.QueryContext(ctx, "GET PROPERTY "+name+" OF DATABASE", nil)
 	if err != nil {
 		return "", fmt.Errorf("cannot get database property: %w", err)
 	}
@@ -110,8 +147,8 @@ func (c *vConn) getDatabasePropertyString(name string) (string, error) {
 	return strval, nil
 }
 
-func (c *vConn) setDatabasePropertyString(name string, value driver.Value) error {
-	_, err := c.ExecContext(context.Background(), "SET PROPERTY "+name+" OF DATABASE TO ?", []driver.NamedValue{
+func (c *vConn) setDatabasePropertyString(ctx context.Context, name string, value driver.Value) error {
+	_, err := c.ExecContext(ctx, "SET PROPERTY "+name+" OF DATABASE TO ?", []driver.NamedValue{
 		{Name: "", Ordinal: 1, Value: value},
 	})
 	if err != nil {
@@ -122,10 +159,18 @@ func (c *vConn) setDatabasePropertyString(name string, value driver.Value) error
 }
 
 func (c *vConn) makeRequest(ctx context.Context, method string, resource string, body any) (*http.Response, error) {
+	// The records of open rows are read first, database/sql allows statements while rows are
+	// open on a Conn or Tx, i.e. within a rows.Next loop
+	if c.streaming != nil {
+		c.streaming.buffer()
+	}
+
 	var payload io.ReadCloser
 	var bodyBytes []byte // To preserve the encoded body
 
-	if body != nil {
+	msg, streamed := body.(vFastSQLRequest)
+	streamed = streamed && msg.streamed()
+	if body != nil && !streamed {
 		buf := new(bytes.Buffer)
 		encoder := json.NewEncoder(buf)
 		if err := encoder.Encode(body); err != nil {
@@ -135,42 +180,79 @@ func (c *vConn) makeRequest(ctx context.Context, method string, resource string,
 		payload = io.NopCloser(bytes.NewReader(bodyBytes)) // Use a fresh reader
 	}
 
-	endpoint := c.restURL.Scheme + "://" + c.restURL.Host + resource
-
-	req, err := http.NewRequestWithContext(ctx, method, endpoint, payload)
+	req, err := http.NewRequestWithContext(ctx, method, c.endpoint+resource, payload)
 	if err != nil {
 		return nil, err
 	}
+	for key, values := range c.header {
+		req.Header[key] = values
+	}
 	req.Header.Set("Content-Type", contentType)
 	req.Header.Set("Accept", acceptType)
+	req.Header.Set("User-Agent", c.userAgent)
 	if c.sessionID != "" {
 		req.Header.Set("Cookie", "sessionID="+c.sessionID)
 	}
+	if c.auth != nil {
+		if err := c.auth.Authorize(req); err != nil {
+			return nil, fmt.Errorf("cannot authorize request: %w", err)
+		}
+	}
 
 	if bodyBytes != nil {
 		req.Body = io.NopCloser(bytes.NewReader(bodyBytes)) // Ensure body is reusable
 		req.ContentLength = int64(len(bodyBytes))           // Set Content-Length for clarity
 	}
 
+	if streamed {
+		// BLOB parameters are encoded while the request is sent, its length is unknown
+		pr, pw := io.Pipe()
+		written := make(chan struct{})
+		go func() {
+			defer close(written)
+			pw.CloseWithError(msg.writeJSON(pw))
+		}()
+		req.Body = pr
+		req.ContentLength = -1
+
+		// The readers must not be used anymore when the statement returns
+		defer func() {
+			pr.Close()
+			<-written
+		}()
+	}
+
+	stats.requests.Add(1)
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
+		c.checkCanceled(ctx)
 		return nil, fmt.Errorf("http request failed: %w", err)
 	}
+	c.lastUsed = time.Now()
+	if c.maxResponseBytes > 0 {
+		resp.Body = &limitedBody{ReadCloser: resp.Body, remaining: c.maxResponseBytes}
+	}
 	return resp, nil
 }
 
-func (c *vConn) createSession() error {
-	ctx := context.Background()
-
-	password, _ := c.restURL.User.Password()
-	hasher := md5.New()
-	hasher.Write([]byte(password))
-	hashBytes := hasher.Sum(nil)
-	hashedPassword := hex.EncodeToString(hashBytes)
+func (c *vConn) createSession(ctx context.Context) error {
+	user, password := c.user, c.password
+	if c.auth != nil {
+		authUser, authPassword, err := c.auth.Credentials(ctx)
+		if err != nil {
+			return fmt.Errorf("cannot get credentials: %w", err)
+		}
+		if authUser != "" {
+			user = authUser
+		}
+		if authPassword != "" {
+			password = authPassword
+		}
+	}
 
 	payload := map[string]string{
-		"user":     c.restURL.User.Username(),
-		"password": hashedPassword,
+		"user":     user,
+		"password": hashPassword(password),
 	}
 
 	resp, err := c.makeRequest(ctx, http.MethodPost, "/rest", payload)
@@ -180,16 +262,7 @@ func (c *vConn) createSession() error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
-		msg, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return fmt.Errorf("unexpected http code: %v (unknown error)", resp.StatusCode)
-		}
-		var verr vError
-		if err := json.Unmarshal(msg, &verr); err == nil {
-			return fmt.Errorf("valentina error: %s", verr.Error)
-		}
-
-		return fmt.Errorf("error: %s", string(msg))
+		return c.readError(resp, "")
 	}
 
 	cookieHeader := resp.Header.Get("Set-Cookie")
@@ -206,6 +279,79 @@ func (c *vConn) createSession() error {
 		return fmt.Errorf("invalid Set-Cookie header")
 	}
 	c.sessionID = sessionID
+	c.created = time.Now()
+	c.expired = false
+	stats.sessionsCreated.Add(1)
+	c.log(ctx, slog.LevelDebug, "valentina session created", slog.String("user", user), slog.String("endpoint", c.endpoint))
 
 	return nil
 }
+
+// withQueryTimeout applies the default query timeout if ctx has no deadline.
+func (c *vConn) withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok || c.queryTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.queryTimeout)
+}
+
+// checkCanceled marks the connection as bad if ctx was canceled or timed out during a request.
+// The server may still be executing the query in our session, so it must not be reused.
+func (c *vConn) checkCanceled(ctx context.Context) {
+	if ctx.Err() != nil {
+		c.bad = true
+	}
+}
+
+// maxErrorBytes limits how much of a failed response is buffered, see bufferErrorBody.
+const maxErrorBytes = 1 << 20
+
+// readError reads the error message from a failed response.
+func (c *vConn) readError(resp *http.Response, query string) error {
+	msg, err := io.ReadAll(resp.Body)
+	if err != nil {
+		msg = nil
+	}
+	return c.bodyError(resp.StatusCode, msg, query)
+}
+
+// bodyError returns the error of a failed response with the body msg, which is a JSON error
+// or a text like the error page of a proxy.
+func (c *vConn) bodyError(statusCode int, msg []byte, query string) *Error {
+	if len(msg) == 0 {
+		return c.newError(statusCode, fmt.Sprintf("unexpected status code: %v", statusCode), query)
+	}
+
+	var verr vError
+	if err := json.Unmarshal(msg, &verr); err == nil && verr.Error != "" {
+		return c.newError(statusCode, verr.Error, query)
+	}
+
+	return c.newError(statusCode, strings.TrimSpace(string(msg)), query)
+}
+
+// bufferErrorBody reads the body of a failed response, so it can still be returned by
+// bodyError if it isn't the JSON the caller decodes. It returns nil for successful responses.
+func bufferErrorBody(resp *http.Response) []byte {
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBytes))
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(msg))
+	return msg
+}
+
+// newError creates an *Error for a message returned by the server.
+func (c *vConn) newError(statusCode int, msg string, query string) *Error {
+	err := &Error{
+		StatusCode: statusCode,
+		Message:    msg,
+		Vendor:     Vendor(c.vendor),
+		Query:      query,
+	}
+	if err.Is(ErrSessionExpired) {
+		c.markExpired()
+	}
+	return err
+}
@@ -8,62 +8,236 @@ import (
 	"context"
 	"database/sql/driver"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"sync"
 )
 
 type Connector struct {
 	vendor Vendor
 	config Config
+
+	customClient    *http.Client      // Set by WithHTTPClient
+	customTransport http.RoundTripper // Set by WithTransport
+	userAgent       string
+	header          http.Header
+	auth            Authenticator // Set by WithAuthenticator
+	logger          *slog.Logger  // Set by WithLogger
+
+	clientOnce sync.Once
+	client     *http.Client // Shared by all connections, see httpClient
+	clientErr  error
+}
+
+// ConnectorOption configures a Connector, see NewConnector.
+type ConnectorOption func(*Connector)
+
+// WithHTTPClient makes the connector use hc for all requests. The timeout and TLS
+// settings of the Config are not applied to it.
+func WithHTTPClient(hc *http.Client) ConnectorOption {
+	return func(c *Connector) {
+		c.customClient = hc
+	}
+}
+
+// WithTransport makes the connector use rt for all requests, i.e. to use a proxy or to trace
+// requests. The timeout and TLS settings of the Config are not applied to it.
+func WithTransport(rt http.RoundTripper) ConnectorOption {
+	return func(c *Connector) {
+		c.customTransport = rt
+	}
+}
+
+// WithUserAgent sets the User-Agent header, replacing the default and Config.ApplicationName.
+func WithUserAgent(userAgent string) ConnectorOption {
+	return func(c *Connector) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithHeader adds a header to all requests.
+func WithHeader(key, value string) ConnectorOption {
+	return func(c *Connector) {
+		if c.header == nil {
+			c.header = make(http.Header)
+		}
+		c.header.Add(key, value)
+	}
 }
 
-func NewConnector(vendor Vendor, config Config) driver.Connector {
-	return Connector{
+// WithAuthenticator makes the connector log in with auth instead of sending the password of
+// the Config as MD5 hash. It takes precedence over Config.Auth.
+func WithAuthenticator(auth Authenticator) ConnectorOption {
+	return func(c *Connector) {
+		c.auth = auth
+	}
+}
+
+// WithLogger makes the connections emit structured events to logger: session creation,
+// expiry and refresh at Debug and Info level, each sql_fast call at Debug level and failed
+// calls at Error level. It takes precedence over Config.Logger.
+func WithLogger(logger *slog.Logger) ConnectorOption {
+	return func(c *Connector) {
+		c.logger = logger
+	}
+}
+
+// NewConnector returns a connector for sql.OpenDB.
+func NewConnector(vendor Vendor, config Config, opts ...ConnectorOption) driver.Connector {
+	c := &Connector{
 		vendor: vendor,
 		config: config,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
-func (c Connector) Connect(ctx context.Context) (driver.Conn, error) {
-	hc := http.Client{
-		// Make sure redirects are not followed
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			return http.ErrUseLastResponse
-		},
+func (c *Connector) Connect(ctx context.Context) (driver.Conn, error) {
+	cfg := c.config
+	if err := cfg.validate(); err != nil {
+		return nil, err
 	}
 
-	conn := vConn{
-		httpClient: &hc,
-		restURL:    c.config.makeURL(),
-		vendor:     string(c.vendor),
+	vendor := c.vendor
+	if cfg.Vendor != "" {
+		vendor = cfg.Vendor
+	}
+	if vendor == "" {
+		vendor = defaultVendor
+	}
+	vendor, err := ParseVendor(string(vendor))
+	if err != nil {
+		return nil, err
 	}
 
-	if err := conn.createSession(); err != nil {
-		return nil, fmt.Errorf("cannot create session: %w", err)
+	hc, err := c.httpClient()
+	if err != nil {
+		return nil, err
 	}
 
-	// Set the date format for this connection. This is required for the time.Time type.
-	// Both proerties are only visible to the current connection and not persisted in the database,
-	// see https://valentina-db.com/docs/dokuwiki/v15/doku.php?id=valentina:vcomponents:vkernel:database:datetime_format&s[]=kymd
-	err := conn.setDatabasePropertyString("DateTimeFormat", kDateFormat)
+	userAgent := c.userAgent
+	if userAgent == "" {
+		userAgent = cfg.userAgent()
+	}
+
+	auth, err := c.authenticator()
 	if err != nil {
-		return nil, fmt.Errorf("cannot set database DateTimeFormat property: %w", err)
+		return nil, err
 	}
 
-	err = conn.setDatabasePropertyString("DateSeparator", kDateSeparater)
+	logger, err := c.loggerFor()
 	if err != nil {
-		return nil, fmt.Errorf("cannot set database DateSeparator property: %w", err)
+		return nil, err
+	}
+
+	conn := vConn{
+		httpClient:       hc,
+		endpoint:         cfg.endpoint(),
+		user:             cfg.User,
+		password:         cfg.Password,
+		database:         cfg.DB,
+		vendor:           string(vendor),
+		queryTimeout:     cfg.QueryTimeout,
+		parseTime:        cfg.ParseTime,
+		autoLastInsertID: cfg.AutoLastInsertID,
+		loc:              cfg.Loc,
+		maxResponseBytes: cfg.MaxResponseBytes,
+		userAgent:        userAgent,
+		header:           c.header,
+		auth:             auth,
+		logger:           logger,
+		logParams:        cfg.LogParams,
+	}
+
+	conn.dateFormat = cfg.DateFormat
+	if conn.dateFormat == "" {
+		conn.dateFormat = kDateFormat
+	}
+	conn.reloginEnabled = cfg.Relogin
+	conn.sessionTimeout = cfg.SessionTimeout
+	if conn.sessionTimeout <= 0 {
+		conn.sessionTimeout = defaultSessionTimeout
+	}
+
+	if err := conn.createSession(ctx); err != nil {
+		conn.log(ctx, slog.LevelError, "valentina connect failed", slog.String("dsn", cfg.RedactedDSN()), slog.String("error", err.Error()))
+		return nil, fmt.Errorf("cannot create session: %w", err)
+	}
+	if err := conn.initSession(ctx); err != nil {
+		conn.Close()
+		return nil, err
 	}
 
 	return &conn, nil
 }
 
-func (c Connector) Driver() driver.Driver {
+// authenticator returns the authenticator of the connector or the Config.
+func (c *Connector) authenticator() (Authenticator, error) {
+	if c.auth != nil {
+		return c.auth, nil
+	}
+	if c.config.Auth == "" {
+		return MD5Auth(c.config.User, c.config.Password), nil
+	}
+	if auth, ok := registeredAuthenticator(c.config.Auth); ok {
+		return auth, nil
+	}
+	return nil, fmt.Errorf("unknown authenticator %q, see RegisterAuthenticator", c.config.Auth)
+}
+
+// loggerFor returns the logger of the connector or the Config, nil if logging is disabled.
+func (c *Connector) loggerFor() (*slog.Logger, error) {
+	if c.logger != nil || c.config.Logger == "" {
+		return c.logger, nil
+	}
+	if logger, ok := registeredLogger(c.config.Logger); ok {
+		return logger, nil
+	}
+	return nil, fmt.Errorf("unknown logger %q, see RegisterLogger", c.config.Logger)
+}
+
+// httpClient returns the client shared by all connections of the connector.
+func (c *Connector) httpClient() (*http.Client, error) {
+	c.clientOnce.Do(func() {
+		var hc http.Client
+		switch {
+		case c.customClient != nil:
+			hc = *c.customClient
+		case c.customTransport != nil:
+			hc.Transport = c.customTransport
+		default:
+			transport, err := c.config.newTransport()
+			if err != nil {
+				c.clientErr = err
+				return
+			}
+			hc.Transport = transport
+		}
+
+		// Make sure redirects are not followed
+		if hc.CheckRedirect == nil {
+			hc.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			}
+		}
+		c.client = &hc
+	})
+
+	return c.client, c.clientErr
+}
+
+func (c *Connector) Driver() driver.Driver {
 	return vDriver{
 		Vendor: c.vendor,
 	}
 }
 
-func (c Connector) Close() error {
+// Close releases the idle HTTP connections, it is called by sql.DB.Close.
+func (c *Connector) Close() error {
+	if c.client != nil && c.customClient == nil {
+		c.client.CloseIdleConnections()
+	}
 	return nil
 }
-
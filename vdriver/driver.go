@@ -5,13 +5,12 @@
 package vdriver
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"net/url"
-	"strings"
 )
 
 const (
@@ -40,49 +39,24 @@ type vDriver struct {
 	Vendor Vendor
 }
 
-func (d vDriver) Open(restURL string) (driver.Conn, error) {
-	hc := http.Client{
-		// Make sure redirects are not followed
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			return http.ErrUseLastResponse
-		},
-	}
-
-	parsedURL, err := url.Parse(restURL)
+// Open connects with a new Connector, database/sql prefers OpenConnector.
+func (d vDriver) Open(dsn string) (driver.Conn, error) {
+	connector, err := d.OpenConnector(dsn)
 	if err != nil {
-		return nil, fmt.Errorf("url malformed: %w", err)
-	}
-
-	database := parsedURL.Path
-	if database != "" {
-		database = strings.TrimPrefix(database, "/")
-	}
-
-	conn := vConn{
-		httpClient: &hc,
-		restURL:    parsedURL,
-		database:   database,
-		vendor:     string(d.Vendor),
+		return nil, err
 	}
 
-	if err := conn.createSession(); err != nil {
-		return nil, fmt.Errorf("cannot create session: %w", err)
-	}
-
-	// Set the date format for this connection. This is required for the time.Time type.
-	// Both proerties are only visible to the current connection and not persisted in the database,
-	// see https://valentina-db.com/docs/dokuwiki/v15/doku.php?id=valentina:vcomponents:vkernel:database:datetime_format&s[]=kymd
-	err = conn.setDatabasePropertyString("DateTimeFormat", kDateFormat)
-	if err != nil {
-		return nil, fmt.Errorf("cannot set database DateTimeFormat property: %w", err)
-	}
+	return connector.Connect(context.Background())
+}
 
-	err = conn.setDatabasePropertyString("DateSeparator", kDateSeparater)
+// OpenConnector parses the DSN, see ParseDSN for the format.
+func (d vDriver) OpenConnector(dsn string) (driver.Connector, error) {
+	cfg, err := ParseDSN(dsn)
 	if err != nil {
-		return nil, fmt.Errorf("cannot set database DateSeparator property: %w", err)
+		return nil, err
 	}
 
-	return &conn, nil
+	return NewConnector(d.Vendor, cfg), nil
 }
 
 type vError struct {
@@ -94,6 +68,7 @@ func readResponseBody[T any](resp *http.Response) (*T, error) {
 
 	var result T
 	enc := json.NewDecoder(resp.Body)
+	enc.UseNumber() // Keep numbers exact, vRows converts them
 	err := enc.Decode(&result)
 	return &result, err
 }
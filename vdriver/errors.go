@@ -0,0 +1,165 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vdriver
+
+import (
+	"database/sql/driver"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// Error categories, use errors.Is to check if an *Error belongs to one of them.
+var (
+	ErrSessionExpired  = errors.New("session expired")
+	ErrSyntax          = errors.New("syntax error")
+	ErrAuthFailed      = errors.New("authentication failed")
+	ErrUniqueViolation = errors.New("unique constraint violation")
+	ErrNotFound        = errors.New("object not found")
+)
+
+const (
+	msgSessionNotFound = "Session does not exist"
+	msgNoResult        = "neither cursor nor affectedRows"
+)
+
+// Error is an error reported by the Valentina Server.
+type Error struct {
+	StatusCode int    // HTTP status code of the response
+	Message    string // Error message as returned by the server
+	Vendor     Vendor
+	Query      string // Empty for session requests
+
+	recreated bool // The session was re-created in place, see vConn.relogin
+}
+
+func (e *Error) Error() string {
+	return "valentina error: " + e.Message
+}
+
+// Messages of the engines per category, matched case-insensitively after quoted identifiers
+// and values were removed from the message, see unquoted.
+var (
+	syntaxMessages = []string{
+		"syntax error", // Valentina, SQLite: near "x": syntax error
+		"parser error", // DuckDB: Parser Error: syntax error at or near
+		"parse error",
+		"incomplete input", // SQLite
+	}
+	uniqueMessages = []string{
+		"unique constraint", // SQLite: UNIQUE constraint failed: t.id
+		"duplicate key",     // DuckDB: Constraint Error: Duplicate key "id: 1" violates primary key constraint
+		"duplicate value",
+		"not unique",
+	}
+	notFoundMessages = []string{
+		"not found",      // Valentina: Table "t" not found, DuckDB: Binder Error: Referenced column "x" not found
+		"does not exist", // DuckDB: Catalog Error: Table with name t does not exist!
+		"no such table:", // SQLite
+		"no such column:",
+		"no such function:",
+		"no such index:",
+		"unknown table",
+		"unknown field",
+		"unknown column",
+	}
+	authMessages = []string{
+		"wrong user name or password", // Valentina
+		"access denied",
+		"authentication failed",
+		"not authorized",
+	}
+)
+
+// Is classifies the error by the status code and the messages the REST API returns.
+// An expired session also matches driver.ErrBadConn, so database/sql retries on a new connection,
+// unless the connection already logged in again.
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrSessionExpired:
+		return e.StatusCode == http.StatusNotFound && e.Message == msgSessionNotFound
+	case driver.ErrBadConn:
+		return e.StatusCode == http.StatusNotFound && e.Message == msgSessionNotFound && !e.recreated
+	case ErrAuthFailed:
+		// Messages of statements may quote any identifier, only login responses are checked
+		if e.StatusCode == http.StatusUnauthorized || e.StatusCode == http.StatusForbidden {
+			return true
+		}
+		return e.Query == "" && containsAny(unquoted(e.Message), authMessages...)
+	case ErrSyntax:
+		return containsAny(unquoted(e.Message), syntaxMessages...)
+	case ErrUniqueViolation:
+		return containsAny(unquoted(e.Message), uniqueMessages...)
+	case ErrNotFound:
+		if e.Message == msgSessionNotFound {
+			return false
+		}
+		return containsAny(unquoted(e.Message), notFoundMessages...)
+	}
+
+	return false
+}
+
+// unquoted returns the lowercase message without the quoted identifiers and values in it,
+// and without the unquoted names after "no such table: " and the like, so names like
+// unique_id or password don't classify the error.
+func unquoted(msg string) string {
+	var sb strings.Builder
+	for i := 0; i < len(msg); i++ {
+		switch ch := msg[i]; ch {
+		case '\'', '"', '`':
+			end := strings.IndexByte(msg[i+1:], ch)
+			if end < 0 {
+				i = len(msg)
+				break
+			}
+			i += end + 1
+			sb.WriteByte(' ')
+		default:
+			sb.WriteByte(ch)
+		}
+	}
+
+	s := strings.ToLower(sb.String())
+	// SQLite appends the name unquoted after a colon
+	if i := strings.Index(s, ": "); i >= 0 && strings.HasPrefix(s, "no such ") {
+		s = s[:i+1]
+	}
+	return s
+}
+
+// IsSessionExpired reports whether err was caused by a REST session the server no longer knows.
+func IsSessionExpired(err error) bool {
+	return errors.Is(err, ErrSessionExpired)
+}
+
+// IsSyntaxError reports whether err was caused by an invalid SQL statement.
+func IsSyntaxError(err error) bool {
+	return errors.Is(err, ErrSyntax)
+}
+
+// IsAuthFailed reports whether err was caused by invalid credentials.
+func IsAuthFailed(err error) bool {
+	return errors.Is(err, ErrAuthFailed)
+}
+
+// IsUniqueViolation reports whether err was caused by a duplicate value in a unique field or index.
+func IsUniqueViolation(err error) bool {
+	return errors.Is(err, ErrUniqueViolation)
+}
+
+// IsNotFound reports whether err was caused by a missing table, field or other object.
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+func containsAny(s string, substrs ...string) bool {
+	for _, substr := range substrs {
+		if strings.Contains(s, substr) {
+			return true
+		}
+	}
+	return false
+}
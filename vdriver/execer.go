@@ -12,6 +12,83 @@ import (
 )
 
 func (c *vConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if c.bad {
+		return nil, driver.ErrBadConn
+	}
+
+	ctx, cancel := c.withQueryTimeout(ctx)
+	defer cancel()
+
+	stmts, err := splitScript(query, args, Vendor(c.vendor))
+	if err != nil {
+		return nil, err
+	}
+
+	// The affected rows of a script are summed up, the last insert ID is the one of the last INSERT
+	var total vResult
+	for i, stmt := range stmts {
+		result, err := c.execStatement(ctx, stmt.query, stmt.args)
+		if err != nil {
+			if len(stmts) == 1 {
+				return nil, err
+			}
+			return nil, c.scriptError(i, err)
+		}
+
+		total.affectedRows += result.affectedRows
+		if result.hasLastInsertId || result.lastInsertIdErr != nil {
+			total.lastInsertId = result.lastInsertId
+			total.hasLastInsertId = result.hasLastInsertId
+			total.lastInsertIdErr = result.lastInsertIdErr
+		}
+	}
+
+	return &total, nil
+}
+
+// execStatement executes a single statement with bound arguments.
+func (c *vConn) execStatement(ctx context.Context, query string, args []driver.NamedValue) (vResult, error) {
+	result, err := c.execOnce(ctx, query, args)
+	if err != nil {
+		if replay, err := c.relogin(ctx, query, err); !replay {
+			return vResult{}, err
+		}
+		return c.execOnce(ctx, query, args)
+	}
+	return result, nil
+}
+
+func (c *vConn) execOnce(ctx context.Context, query string, args []driver.NamedValue) (vResult, error) {
+	insert := c.autoLastInsertID && isInsert(query, Vendor(c.vendor))
+	if insert && Vendor(c.vendor) == VendorDuckDB {
+		if returning, ok := withReturningRowID(query); ok {
+			return c.execReturning(ctx, returning, args)
+		}
+	}
+
+	result, err := c.execFast(ctx, query, args)
+	if err != nil {
+		return vResult{}, err
+	}
+	if insert && result.affectedRows > 0 && Vendor(c.vendor) != VendorDuckDB {
+		// The statement succeeded, so a failed lookup must not fail it
+		result.lastInsertId, result.lastInsertIdErr = c.lastInsertID(ctx)
+		result.hasLastInsertId = true
+	}
+
+	return result, nil
+}
+
+// execFast sends a statement through sql_fast and returns its affected rows.
+func (c *vConn) execFast(ctx context.Context, query string, args []driver.NamedValue) (_ vResult, err error) {
+	log := c.newSQLLog(ctx, query, args)
+	defer func() {
+		if log != nil {
+			log.err = err
+			log.done()
+		}
+	}()
+
 	// Use Fast SQL
 	msg := vFastSQLRequest{
 		Vendor:   c.vendor,
@@ -27,35 +104,33 @@ func (c *vConn) ExecContext(ctx context.Context, query string, args []driver.Nam
 	}
 
 	resp, err := c.makeRequest(ctx, http.MethodPost, "/rest/session_id/sql_fast", msg)
+	c.invalidateColumns(query)
 	if err != nil {
-		return nil, fmt.Errorf("makeRequest failed: %w", err)
+		return vResult{}, fmt.Errorf("makeRequest failed: %w", err)
 	}
 
+	errBody := bufferErrorBody(resp)
 	response, err := readResponseBody[vFastSQLResponse](resp)
 	if err != nil {
-		return nil, fmt.Errorf("json decoding failed: %w", err)
+		c.checkCanceled(ctx)
+		if resp.StatusCode != http.StatusOK {
+			return vResult{}, c.bodyError(resp.StatusCode, errBody, query)
+		}
+		return vResult{}, fmt.Errorf("json decoding failed: %w", err)
 	}
+	log.response(resp.StatusCode, response.AffectedRows)
 	if response.Error != "" {
-		// Session expired, tell Go to refresh it
-		if resp.StatusCode == http.StatusNotFound && response.Error == "Session does not exist" {
-			return nil, driver.ErrBadConn
-		}
-
 		// This is a special case for statements that have no rows and no effect, like "SET PROPERTY ..."
-		if response.Error == "neither cursor nor affectedRows" {
+		if response.Error == msgNoResult {
 			return vResult{}, nil
 		}
 
-		return nil, fmt.Errorf("valentina error: %s", response.Error)
+		// An expired session matches driver.ErrBadConn, which tells Go to refresh it
+		return vResult{}, c.newError(resp.StatusCode, response.Error, query)
 	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %v", resp.StatusCode)
-	}
-
-	result := vResult{
-		affectedRows: response.AffectedRows,
-		lastInsertId: 0, // TODO: is this supported?
+		return vResult{}, c.newError(resp.StatusCode, fmt.Sprintf("unexpected status code: %v", resp.StatusCode), query)
 	}
 
-	return &result, nil
+	return vResult{affectedRows: response.AffectedRows}, nil
 }
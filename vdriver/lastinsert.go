@@ -0,0 +1,117 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vdriver
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// The REST API doesn't return the ID of inserted records. With lastInsertId=auto, it is
+// queried on the same session after an INSERT: Valentina has Last_RecID(), SQLite
+// last_insert_rowid() and on DuckDB the INSERT is sent with RETURNING rowid.
+
+// isInsert reports whether query is an INSERT (or SQLite's REPLACE) statement.
+func isInsert(query string, vendor Vendor) bool {
+	l := newLexer(query, vendor)
+	for {
+		tok, ok := l.next()
+		if !ok {
+			return false
+		}
+		if tok.kind == tokComment {
+			continue
+		}
+		fields := strings.Fields(tok.text)
+		if len(fields) == 0 {
+			continue
+		}
+		return strings.EqualFold(fields[0], "INSERT") || strings.EqualFold(fields[0], "REPLACE")
+	}
+}
+
+// withReturningRowID appends RETURNING rowid to an INSERT on DuckDB, ok is false if it
+// already has a RETURNING clause.
+func withReturningRowID(query string) (string, bool) {
+	end := 0
+	l := newLexer(query, VendorDuckDB)
+	for {
+		tok, ok := l.next()
+		if !ok {
+			break
+		}
+		switch tok.kind {
+		case tokComment, tokSemicolon:
+			continue
+		case tokText:
+			for _, word := range strings.Fields(tok.text) {
+				if strings.EqualFold(word, "RETURNING") {
+					return query, false
+				}
+			}
+			if strings.TrimSpace(tok.text) == "" {
+				continue
+			}
+			end = tok.pos + len(strings.TrimRight(tok.text, " \t\r\n"))
+		default:
+			end = tok.pos + len(tok.text)
+		}
+	}
+	return query[:end] + " RETURNING rowid", true
+}
+
+// execReturning executes an INSERT on DuckDB and returns the rowid of the last inserted record.
+func (c *vConn) execReturning(ctx context.Context, query string, args []driver.NamedValue) (vResult, error) {
+	rows, err := c.QueryContext(ctx, query, args)
+	if err != nil {
+		return vResult{}, err
+	}
+	defer rows.Close()
+
+	var result vResult
+	values := make([]driver.Value, len(rows.Columns()))
+	for {
+		err := rows.Next(values)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return vResult{}, err
+		}
+		result.affectedRows++
+		if len(values) > 0 {
+			result.lastInsertId = toInt64(values[0])
+		}
+	}
+	result.hasLastInsertId = result.affectedRows > 0
+
+	return result, nil
+}
+
+// lastInsertID returns the ID of the last record inserted within the session.
+func (c *vConn) lastInsertID(ctx context.Context) (int64, error) {
+	query := "SELECT Last_RecID()"
+	if Vendor(c.vendor) == VendorSQLite {
+		query = "SELECT last_insert_rowid()"
+	}
+
+	rows, err := c.QueryContext(ctx, query, nil)
+	if err != nil {
+		return 0, fmt.Errorf("cannot get last insert ID: %w", err)
+	}
+	defer rows.Close()
+
+	values := make([]driver.Value, len(rows.Columns()))
+	if len(values) == 0 {
+		return 0, fmt.Errorf("cannot get last insert ID: unexpected result")
+	}
+	if err := rows.Next(values); err != nil {
+		return 0, fmt.Errorf("cannot get last insert ID: %w", err)
+	}
+	return toInt64(values[0]), nil
+}
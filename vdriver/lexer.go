@@ -0,0 +1,236 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vdriver
+
+import (
+	"strconv"
+	"strings"
+)
+
+// The lexer splits a query into the tokens the driver cares about: literals, quoted identifiers
+// and comments, which must be left untouched, placeholders and statement separators.
+// Everything else is returned as text.
+
+type tokenKind int
+
+const (
+	tokText        tokenKind = iota
+	tokString                // 'literal' or $tag$literal$tag$
+	tokQuotedIdent           // "ident", `ident` or [ident], except on DuckDB
+	tokComment               // -- comment or /* comment */
+	tokPositional            // ?
+	tokOrdinal               // :1
+	tokNamed                 // :name or @name
+	tokOtherParam            // ?1, $1 or $name, which Valentina doesn't support but SQLite and DuckDB do
+	tokSemicolon
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int // Byte offset in the query
+}
+
+type lexer struct {
+	query    string
+	pos      int
+	brackets bool // [ident] is a quoted identifier, on DuckDB brackets are lists and subscripts
+	depth    int  // Nesting of DuckDB lists, subscripts and structs, whose colons are no placeholders
+}
+
+func newLexer(query string, vendor Vendor) *lexer {
+	return &lexer{query: query, brackets: vendor != VendorDuckDB}
+}
+
+// next returns the next token, ok is false at the end of the query.
+func (l *lexer) next() (tok token, ok bool) {
+	if l.pos >= len(l.query) {
+		return token{}, false
+	}
+
+	start := l.pos
+	if kind, end, ok := l.scanToken(start); ok {
+		l.pos = end
+		if kind == tokText {
+			l.nest(l.query[start])
+		}
+		return token{kind: kind, text: l.query[start:end], pos: start}, true
+	}
+
+	// Text runs up to the start of the next token
+	end := start + 1
+	for end < len(l.query) {
+		if _, _, ok := l.scanToken(end); ok {
+			break
+		}
+		end++
+	}
+	l.pos = end
+	return token{kind: tokText, text: l.query[start:end], pos: start}, true
+}
+
+// scanToken returns the kind and end of the token starting at i, ok is false for text.
+func (l *lexer) scanToken(i int) (kind tokenKind, end int, ok bool) {
+	q := l.query
+	switch ch := q[i]; ch {
+	case '\'':
+		return tokString, skipQuoted(q, i, '\''), true
+	case '"', '`':
+		return tokQuotedIdent, skipQuoted(q, i, ch), true
+	case '[':
+		if l.brackets {
+			return tokQuotedIdent, skipQuoted(q, i, ']'), true
+		}
+		return tokText, i + 1, true
+	case '{', ']', '}':
+		// Brackets and braces are tokens of their own on DuckDB, so next can track their depth
+		if !l.brackets {
+			return tokText, i + 1, true
+		}
+	case '-':
+		if strings.HasPrefix(q[i:], "--") {
+			return tokComment, skipLine(q, i), true
+		}
+	case '/':
+		if strings.HasPrefix(q[i:], "/*") {
+			return tokComment, skipBlockComment(q, i), true
+		}
+	case ';':
+		return tokSemicolon, i + 1, true
+	case '?':
+		if e := skipIdent(q, i+1); e > i+1 {
+			return tokOtherParam, e, true
+		}
+		return tokPositional, i + 1, true
+	case '$':
+		// $ is also part of identifiers and dollar-quoted strings, only $1 and $name count
+		if i > 0 && isIdentChar(q[i-1]) {
+			return
+		}
+		e := skipIdent(q, i+1)
+		if e < len(q) && q[e] == '$' && (e == i+1 || !isDigits(q[i+1:i+2])) {
+			return tokString, skipDollarQuoted(q, i, q[i:e+1]), true
+		}
+		if e > i+1 {
+			return tokOtherParam, e, true
+		}
+	case ':', '@':
+		// Casts (::) and variables (@@name) are text
+		if i > 0 && q[i-1] == ch || i+1 < len(q) && q[i+1] == ch {
+			return
+		}
+		// Colons in DuckDB slices (l[1:2]) and struct literals ({'a': 1}) are text
+		if ch == ':' && l.depth > 0 {
+			return
+		}
+		e := skipIdent(q, i+1)
+		if e == i+1 {
+			return
+		}
+		if ch == ':' && isDigits(q[i+1:e]) {
+			return tokOrdinal, e, true
+		}
+		return tokNamed, e, true
+	}
+	return
+}
+
+// nest tracks the depth of DuckDB brackets and braces.
+func (l *lexer) nest(ch byte) {
+	switch ch {
+	case '[', '{':
+		l.depth++
+	case ']', '}':
+		l.depth = max(l.depth-1, 0)
+	}
+}
+
+// numInput returns the number of arguments query expects, or -1 if it can't tell,
+// i.e. for named placeholders or mixed placeholder styles.
+func numInput(query string, vendor Vendor) int {
+	positional, maxOrdinal := 0, 0
+
+	l := newLexer(query, vendor)
+	for {
+		tok, ok := l.next()
+		if !ok {
+			break
+		}
+		switch tok.kind {
+		case tokPositional:
+			positional++
+		case tokOrdinal:
+			n, err := strconv.Atoi(tok.text[1:])
+			if err != nil {
+				return -1
+			}
+			maxOrdinal = max(maxOrdinal, n)
+		case tokNamed, tokOtherParam:
+			return -1
+		}
+	}
+
+	if positional > 0 && maxOrdinal > 0 {
+		return -1
+	}
+	return positional + maxOrdinal
+}
+
+// skipQuoted returns the position after the literal or quoted identifier starting at i.
+// Doubled closing characters are escapes.
+func skipQuoted(query string, i int, closing byte) int {
+	for i++; i < len(query); i++ {
+		if query[i] == closing {
+			if i+1 < len(query) && query[i+1] == closing {
+				i++
+				continue
+			}
+			return i + 1
+		}
+	}
+	return len(query)
+}
+
+func skipLine(query string, i int) int {
+	if end := strings.IndexByte(query[i:], '\n'); end >= 0 {
+		return i + end + 1
+	}
+	return len(query)
+}
+
+func skipBlockComment(query string, i int) int {
+	if end := strings.Index(query[i+2:], "*/"); end >= 0 {
+		return i + 2 + end + 2
+	}
+	return len(query)
+}
+
+// skipDollarQuoted returns the end of the string starting at i with the tag $$ or $name$.
+func skipDollarQuoted(query string, i int, tag string) int {
+	if end := strings.Index(query[i+len(tag):], tag); end >= 0 {
+		return i + len(tag) + end + len(tag)
+	}
+	return len(query)
+}
+
+func skipIdent(query string, i int) int {
+	for i < len(query) && isIdentChar(query[i]) {
+		i++
+	}
+	return i
+}
+
+func isIdentChar(ch byte) bool {
+	return ch == '_' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9'
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
@@ -0,0 +1,127 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vdriver
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+var (
+	loggerLock     sync.RWMutex
+	loggerRegistry = make(map[string]*slog.Logger)
+)
+
+// RegisterLogger registers a logger, which can be used with the DSN parameter logger=name.
+// The name "default" is reserved for slog.Default().
+//
+//	vdriver.RegisterLogger("json", slog.New(slog.NewJSONHandler(os.Stderr, nil)))
+//	db, err := sql.Open("valentina", "http://sa:sa@localhost:19998/testdb?logger=json")
+func RegisterLogger(name string, logger *slog.Logger) error {
+	switch name {
+	case "", "default":
+		return fmt.Errorf("logger name %q is reserved", name)
+	}
+	if logger == nil {
+		return fmt.Errorf("logger %q is nil", name)
+	}
+
+	loggerLock.Lock()
+	defer loggerLock.Unlock()
+	loggerRegistry[name] = logger
+	return nil
+}
+
+// DeregisterLogger removes a logger registered with RegisterLogger.
+func DeregisterLogger(name string) {
+	loggerLock.Lock()
+	defer loggerLock.Unlock()
+	delete(loggerRegistry, name)
+}
+
+func registeredLogger(name string) (*slog.Logger, bool) {
+	if name == "default" {
+		return slog.Default(), true
+	}
+
+	loggerLock.RLock()
+	defer loggerLock.RUnlock()
+	logger, ok := loggerRegistry[name]
+	return logger, ok
+}
+
+// log emits an event if the connection has a logger.
+func (c *vConn) log(ctx context.Context, level slog.Level, msg string, attrs ...slog.Attr) {
+	if c.logger == nil {
+		return
+	}
+	c.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// sqlLog collects the attributes of a sql_fast call until its result is read completely.
+type sqlLog struct {
+	conn         *vConn
+	ctx          context.Context
+	query        string
+	args         []driver.NamedValue
+	start        time.Time
+	status       int
+	affectedRows int64
+	rows         int // Records returned, -1 if the statement returned no table
+	err          error
+}
+
+// newSQLLog starts the log of a sql_fast call, it returns nil without a logger.
+func (c *vConn) newSQLLog(ctx context.Context, query string, args []driver.NamedValue) *sqlLog {
+	if c.logger == nil {
+		return nil
+	}
+	return &sqlLog{conn: c, ctx: ctx, query: query, args: args, start: time.Now(), rows: -1}
+}
+
+// response records the status and affected rows of the call.
+func (l *sqlLog) response(status int, affectedRows int64) {
+	if l == nil {
+		return
+	}
+	l.status, l.affectedRows = status, affectedRows
+}
+
+// done emits the event of the call. Failed calls are logged as errors, parameter values
+// only with the logParams option.
+func (l *sqlLog) done() {
+	if l == nil {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("query", l.query),
+		slog.Int("args", len(l.args)),
+		slog.Duration("duration", time.Since(l.start)),
+		slog.Int("status", l.status),
+		slog.Int64("affected_rows", l.affectedRows),
+	}
+	if l.rows >= 0 {
+		attrs = append(attrs, slog.Int("rows", l.rows))
+	}
+	if l.conn.logParams && len(l.args) > 0 {
+		params := make([]any, len(l.args))
+		for i, arg := range l.args {
+			params[i] = arg.Value
+		}
+		attrs = append(attrs, slog.Any("params", params))
+	}
+
+	level := slog.LevelDebug
+	if l.err != nil {
+		level = slog.LevelError
+		attrs = append(attrs, slog.String("error", l.err.Error()))
+	}
+	l.conn.log(l.ctx, level, "valentina sql_fast", attrs...)
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vdriver
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Valentina only knows ordinal placeholders (:1, :2, ...) and ?. Named arguments, see sql.Named,
+// are bound by rewriting :name and @name placeholders into the ordinal of their argument.
+
+// bindNamed rewrites the named placeholders of query into ordinals of args.
+// Queries without named arguments are returned unchanged.
+func bindNamed(query string, args []driver.NamedValue, vendor Vendor) (string, error) {
+	ordinals := make(map[string]int)
+	for i, arg := range args {
+		if arg.Name == "" {
+			continue
+		}
+		if len(ordinals) != i {
+			return "", fmt.Errorf("cannot mix named and positional arguments")
+		}
+		ordinals[arg.Name] = i + 1
+	}
+	if len(ordinals) == 0 {
+		return query, nil
+	}
+	if len(ordinals) != len(args) {
+		return "", fmt.Errorf("cannot mix named and positional arguments")
+	}
+
+	var sb strings.Builder
+	used := make(map[string]bool)
+
+	l := newLexer(query, vendor)
+	for {
+		tok, ok := l.next()
+		if !ok {
+			break
+		}
+
+		switch tok.kind {
+		case tokPositional, tokOrdinal:
+			return "", fmt.Errorf("cannot mix named arguments and positional placeholders")
+		case tokNamed:
+			name := tok.text[1:]
+			ordinal, ok := ordinals[name]
+			if !ok {
+				return "", fmt.Errorf("placeholder %s has no named argument", tok.text)
+			}
+			used[name] = true
+			sb.WriteString(":" + strconv.Itoa(ordinal))
+		default:
+			sb.WriteString(tok.text)
+		}
+	}
+
+	for name := range ordinals {
+		if !used[name] {
+			return "", fmt.Errorf("named argument %q has no placeholder", name)
+		}
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,217 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vdriver
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parameters are sent as JSON values, so everything JSON can't express the way the server
+// expects is converted into the literal Valentina parses.
+
+// CheckNamedValue converts a parameter into a value that can be sent to the server.
+func (c *vConn) CheckNamedValue(nv *driver.NamedValue) error {
+	value, err := c.convertParam(nv.Value)
+	if err != nil {
+		return err
+	}
+	nv.Value = value
+	return nil
+}
+
+// dateValuer is implemented by vsql.Date. Dates aren't points in time, so they are sent in
+// the date format of the session without converting them into loc.
+type dateValuer interface {
+	DateValue() (year int, month time.Month, day int)
+}
+
+// convertParam converts time.Time into the date format of the session, []byte into hex
+// encoded BLOBs, io.Reader into a streamed blobParam and slices into ARRAY literals.
+// json.RawMessage is text. Valuers are converted by their Value.
+func (c *vConn) convertParam(v any) (any, error) {
+	switch v := v.(type) {
+	case nil, string, bool, int64, float64:
+		return v, nil
+	case time.Time:
+		// Results are parsed in loc, parameters must be formatted in the same location
+		loc := c.loc
+		if loc == nil {
+			loc = time.UTC
+		}
+		v = v.In(loc)
+		return v.Format(c.dateLayout() + " 15:04:05.000"), nil
+	case json.RawMessage:
+		if v == nil {
+			return nil, nil
+		}
+		return string(v), nil
+	case []byte:
+		if v == nil {
+			return nil, nil
+		}
+		return c.blobParam(v)
+	case io.Reader:
+		if err := c.checkBlobParam(); err != nil {
+			return nil, err
+		}
+		return newBlobParam(v), nil
+	case driver.Valuer:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Pointer && rv.IsNil() {
+			return nil, nil
+		}
+		if d, ok := v.(dateValuer); ok {
+			year, month, day := d.DateValue()
+			return time.Date(year, month, day, 0, 0, 0, 0, time.UTC).Format(c.dateLayout()), nil
+		}
+		value, err := v.Value()
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := value.(driver.Valuer); ok {
+			return nil, fmt.Errorf("unsupported parameter type %T: Value returned a Valuer", v)
+		}
+		return c.convertParam(value)
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return nil, nil
+		}
+		return c.convertParam(rv.Elem().Interface())
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			if rv.Kind() == reflect.Slice && rv.IsNil() {
+				return nil, nil
+			}
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return c.blobParam(b)
+		}
+		return c.formatArray(rv)
+	case reflect.Uint, reflect.Uint64:
+		return rv.Uint(), nil // JSON encodes values above math.MaxInt64 exactly
+	}
+
+	value, err := driver.DefaultParameterConverter.ConvertValue(v)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported parameter type %T", v)
+	}
+	return c.convertParam(value)
+}
+
+// blobParam returns the hex encoded BLOB b.
+func (c *vConn) blobParam(b []byte) (any, error) {
+	if err := c.checkBlobParam(); err != nil {
+		return nil, err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// checkBlobParam fails on SQLite and DuckDB. The REST API binds the JSON strings of their
+// parameters as text, so a hex encoded BLOB would be stored as its hex digits.
+func (c *vConn) checkBlobParam() error {
+	if Vendor(c.vendor) != VendorValentina {
+		return fmt.Errorf("%w: BLOB parameters on %s, pass a hex string to unhex() instead", ErrNotSupported, c.vendor)
+	}
+	return nil
+}
+
+// formatArray returns the ARRAY literal of a slice, i.e. {1,2,3} or {"a","b"}.
+// DuckDB uses brackets for list literals.
+func (c *vConn) formatArray(rv reflect.Value) (any, error) {
+	if rv.Kind() == reflect.Slice && rv.IsNil() {
+		return nil, nil
+	}
+
+	open, closing := "{", "}"
+	if Vendor(c.vendor) == VendorDuckDB {
+		open, closing = "[", "]"
+	}
+
+	var sb strings.Builder
+	sb.WriteString(open)
+	for i := 0; i < rv.Len(); i++ {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+
+		elem := rv.Index(i)
+		for elem.Kind() == reflect.Interface || elem.Kind() == reflect.Pointer {
+			if elem.IsNil() {
+				break
+			}
+			elem = elem.Elem()
+		}
+		if (elem.Kind() == reflect.Slice || elem.Kind() == reflect.Array) && elem.Type().Elem().Kind() != reflect.Uint8 {
+			if _, ok := elem.Interface().(driver.Valuer); !ok {
+				return nil, fmt.Errorf("unsupported parameter type %s: nested arrays are not supported", rv.Type())
+			}
+		}
+
+		value, err := c.convertParam(elem.Interface())
+		if err != nil {
+			return nil, fmt.Errorf("array element %d: %w", i, err)
+		}
+
+		if blob, ok := value.(*blobParam); ok {
+			if value, err = blob.hexString(); err != nil {
+				return nil, fmt.Errorf("array element %d: %w", i, err)
+			}
+		}
+
+		switch value := value.(type) {
+		case nil:
+			sb.WriteString("NULL")
+		case string:
+			sb.WriteString(quoteArrayElem(value))
+		case bool:
+			sb.WriteString(strconv.FormatBool(value))
+		case int64:
+			sb.WriteString(strconv.FormatInt(value, 10))
+		case uint64:
+			sb.WriteString(strconv.FormatUint(value, 10))
+		case float64:
+			sb.WriteString(strconv.FormatFloat(value, 'g', -1, 64))
+		default:
+			return nil, fmt.Errorf("array element %d: unsupported parameter type %T", i, value)
+		}
+	}
+	sb.WriteString(closing)
+
+	return sb.String(), nil
+}
+
+func quoteArrayElem(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	return `"` + s + `"`
+}
+
+// dateLayout returns the layout of dates matching the DateTimeFormat and DateSeparator of the
+// session. SQLite and DuckDB only know ISO dates.
+func (c *vConn) dateLayout() string {
+	if Vendor(c.vendor) != VendorValentina {
+		return "2006-01-02"
+	}
+
+	switch c.dateFormat {
+	case "kDMY":
+		return "02" + kDateSeparater + "01" + kDateSeparater + "2006"
+	case "kMDY":
+		return "01" + kDateSeparater + "02" + kDateSeparater + "2006"
+	}
+	return "2006" + kDateSeparater + "01" + kDateSeparater + "02"
+}
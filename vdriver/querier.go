@@ -12,6 +12,73 @@ import (
 )
 
 func (c *vConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if c.bad {
+		return nil, driver.ErrBadConn
+	}
+
+	// The rows keep the context until they are closed
+	ctx, cancel := c.withQueryTimeout(ctx)
+
+	stmts, err := splitScript(query, args, Vendor(c.vendor))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	// The statements of a script are executed one at a time by NextResultSet
+	script := len(stmts) > 1
+	rows, err := c.queryStatement(ctx, stmts[0].query, stmts[0].args, script)
+	if err != nil {
+		cancel()
+		if script {
+			return nil, c.scriptError(0, err)
+		}
+		return nil, err
+	}
+	rows.conn, rows.ctx, rows.cancel = c, ctx, cancel
+	rows.pending = stmts[1:]
+
+	return rows, nil
+}
+
+// queryStatement executes a single statement with bound arguments. If emptyResult is set,
+// statements without a result return empty rows instead of an error.
+func (c *vConn) queryStatement(ctx context.Context, query string, args []driver.NamedValue, emptyResult bool) (*vRows, error) {
+	rows, err := c.queryOnce(ctx, query, args, emptyResult)
+	if err != nil {
+		if replay, err := c.relogin(ctx, query, err); !replay {
+			return nil, err
+		}
+		return c.queryOnce(ctx, query, args, emptyResult)
+	}
+	return rows, nil
+}
+
+func (c *vConn) queryOnce(ctx context.Context, query string, args []driver.NamedValue, emptyResult bool) (result *vRows, err error) {
+	log := c.newSQLLog(ctx, query, args)
+	defer func() {
+		if log == nil {
+			return
+		}
+		log.err = err
+		// Only the rows of a Result_Table have a connection
+		if result != nil && result.conn != nil {
+			log.rows = len(result.records)
+			if result.body != nil {
+				result.log = log // Logged when the records were read, see closeBody
+				return
+			}
+		}
+		log.done()
+	}()
+
+	// The column types are looked up first, another request would buffer the streamed records
+	var cols []columnType
+	table, items := sourceTable(query)
+	if table != "" {
+		cols = c.tableColumns(ctx, table)
+	}
+
 	// Use Fast SQL
 	msg := vFastSQLRequest{
 		Vendor:   c.vendor,
@@ -27,24 +94,37 @@ func (c *vConn) QueryContext(ctx context.Context, query string, args []driver.Na
 	}
 
 	resp, err := c.makeRequest(ctx, http.MethodPost, "/rest/session_id/sql_fast", msg)
+	c.invalidateColumns(query)
 	if err != nil {
 		return nil, fmt.Errorf("makeRequest failed: %w", err)
 	}
 
-	response, err := readResponseBody[vFastSQLResponse](resp)
+	errBody := bufferErrorBody(resp)
+	response, dec, err := readRowsResponse(resp)
+	if response != nil {
+		log.response(resp.StatusCode, response.AffectedRows)
+	}
+	if dec == nil {
+		resp.Body.Close()
+	}
 	if err != nil {
+		resp.Body.Close()
+		c.checkCanceled(ctx)
+		if resp.StatusCode != http.StatusOK {
+			return nil, c.bodyError(resp.StatusCode, errBody, query)
+		}
 		return nil, fmt.Errorf("json decoding failed: %w", err)
 	}
 	if response.Error != "" {
-		// Session expired, tell Go to refresh it
-		if resp.StatusCode == http.StatusNotFound && response.Error == "Session does not exist" {
-			return nil, driver.ErrBadConn
+		if emptyResult && response.Error == msgNoResult {
+			return &vRows{}, nil
 		}
 
-		return nil, fmt.Errorf("valentina error: %s", response.Error)
+		// An expired session matches driver.ErrBadConn, which tells Go to refresh it
+		return nil, c.newError(resp.StatusCode, response.Error, query)
 	}
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %v", resp.StatusCode)
+		return nil, c.newError(resp.StatusCode, fmt.Sprintf("unexpected status code: %v", resp.StatusCode), query)
 	}
 
 	// We can either have a Result_Table or AffectedRows (in case user is not using Execer)
@@ -54,6 +134,14 @@ func (c *vConn) QueryContext(ctx context.Context, query string, args []driver.Na
 		rows.columns = response.Fields
 		rows.records = response.Records
 		rows.pos = 0
+		rows.conn = c
+		rows.ctx = ctx
+		rows.types = fieldTypes(response.Fields, items, cols)
+		if dec != nil {
+			rows.body = resp.Body
+			rows.dec = dec
+			c.streaming = &rows
+		}
 		return &rows, nil
 	case response.Name == "" && response.AffectedRows > 0:
 		// We artificially create a affected_rows row
@@ -64,6 +152,10 @@ func (c *vConn) QueryContext(ctx context.Context, query string, args []driver.Na
 		return &rows, nil
 	}
 
+	if emptyResult && response.Name == "" {
+		return &vRows{}, nil
+	}
+
 	// Still here? Then we have an error
 	return nil, fmt.Errorf("unexpected response type: %s", response.Name)
 }
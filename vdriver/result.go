@@ -6,11 +6,20 @@ package vdriver
 
 type vResult struct {
 	affectedRows int64
-	lastInsertId int64
+
+	lastInsertId    int64
+	hasLastInsertId bool  // Only set with lastInsertId=auto
+	lastInsertIdErr error // Looking up the ID failed after the statement succeeded
 }
 
 func (r vResult) LastInsertId() (int64, error) {
-	return 0, ErrNotSupported
+	if r.lastInsertIdErr != nil {
+		return 0, r.lastInsertIdErr
+	}
+	if !r.hasLastInsertId {
+		return 0, ErrNotSupported
+	}
+	return r.lastInsertId, nil
 }
 
 func (r vResult) RowsAffected() (int64, error) {
@@ -5,32 +5,343 @@
 package vdriver
 
 import (
+	"context"
 	"database/sql/driver"
+	"encoding/json"
+	"fmt"
 	"io"
+	"net/http"
+	"reflect"
+	"strings"
 )
 
+// maxDrainBytes is the most we read from an unfinished response on Close, so the HTTP
+// connection can be reused. Larger remainders are aborted by closing the body.
+const maxDrainBytes = 64 << 10
+
 type vRows struct {
 	columns []string
-	records [][]any
+	records [][]any // Buffered records, nil if they are streamed from body
 	pos     int
+
+	body   io.ReadCloser      // Open response body while records are streamed
+	dec    *json.Decoder      // Positioned within the records array of body
+	cancel context.CancelFunc // Releases the query timeout, if any
+
+	pending []statement // Remaining statements of a script, see NextResultSet
+	index   int         // Index of the current statement within the script
+
+	conn  *vConn
+	ctx   context.Context // Context of the query
+	types []columnType    // Types of the columns, looked up before the records are read
+
+	log *sqlLog // Emitted when the streamed records were read, nil without a logger
+	err error   // Failure while the records were buffered, returned after them
 }
 
 func (rows *vRows) Columns() []string {
 	return rows.columns
 }
 
-func (rows *vRows) Close() error { return nil }
+// Close executes the statements of a script that weren't advanced to with NextResultSet, so
+// the script runs completely, i.e. with QueryRow.
+func (rows *vRows) Close() error {
+	err := rows.closeBody()
+	if err == nil && len(rows.pending) > 0 {
+		err = rows.execPending()
+	}
+	rows.pending = nil
+	if rows.cancel != nil {
+		rows.cancel()
+		rows.cancel = nil
+	}
+	return err
+}
 
-func (rows *vRows) Next(dest []driver.Value) error {
-	rows.pos++
-	if rows.pos > len(rows.records) {
+// execPending executes the remaining statements of a script and discards their results.
+func (rows *vRows) execPending() error {
+	for i, stmt := range rows.pending {
+		index := rows.index + 1 + i
+		if rows.conn.bad {
+			return fmt.Errorf("statement %d: not executed, the connection can't be used anymore", index+1)
+		}
+		if _, err := rows.conn.execStatement(rows.ctx, stmt.query, stmt.args); err != nil {
+			return rows.conn.scriptError(index, err)
+		}
+	}
+	return nil
+}
+
+// closeBody closes the response of the current result set.
+func (rows *vRows) closeBody() error {
+	if rows.body == nil {
+		return nil
+	}
+
+	body := rows.body
+	rows.body, rows.dec = nil, nil
+	if rows.conn != nil && rows.conn.streaming == rows {
+		rows.conn.streaming = nil
+	}
+	rows.log.done()
+	rows.log = nil
+
+	_, _ = io.CopyN(io.Discard, body, maxDrainBytes)
+	return body.Close()
+}
+
+func (rows *vRows) HasNextResultSet() bool {
+	return len(rows.pending) > 0
+}
+
+// NextResultSet executes the next statement of a script.
+func (rows *vRows) NextResultSet() error {
+	if len(rows.pending) == 0 {
 		return io.EOF
 	}
+	if err := rows.closeBody(); err != nil {
+		return err
+	}
+
+	stmt := rows.pending[0]
+	index := rows.index + 1
+	next, err := rows.conn.queryStatement(rows.ctx, stmt.query, stmt.args, true)
+	if err != nil {
+		rows.pending = nil
+		return rows.conn.scriptError(index, err)
+	}
+
+	next.conn, next.ctx, next.cancel = rows.conn, rows.ctx, rows.cancel
+	next.pending, next.index = rows.pending[1:], index
+	*rows = *next
+	if rows.body != nil {
+		rows.conn.streaming = rows
+	}
+	return nil
+}
+
+// buffer reads the remaining streamed records and closes the response, so another request
+// can be sent on the session. A decoding error is returned by Next after the records.
+func (rows *vRows) buffer() {
+	var records [][]any
+	for rows.dec.More() {
+		var row []any
+		if err := rows.dec.Decode(&row); err != nil {
+			rows.err = fmt.Errorf("json decoding failed: %w", err)
+			if rows.log != nil {
+				rows.log.err = err
+			}
+			break
+		}
+		records = append(records, row)
+		if rows.log != nil {
+			rows.log.rows++
+		}
+	}
+	rows.records, rows.pos = records, 0
+	rows.closeBody()
+}
+
+func (rows *vRows) Next(dest []driver.Value) error {
+	var row []any
+
+	if rows.body != nil {
+		if !rows.dec.More() {
+			rows.closeBody()
+			return io.EOF
+		}
+		if err := rows.dec.Decode(&row); err != nil {
+			rows.conn.checkCanceled(rows.ctx)
+			if rows.log != nil {
+				rows.log.err = err
+			}
+			rows.closeBody()
+			return fmt.Errorf("json decoding failed: %w", err)
+		}
+		if rows.log != nil {
+			rows.log.rows++
+		}
+	} else {
+		rows.pos++
+		if rows.pos > len(rows.records) {
+			if rows.err != nil {
+				return rows.err
+			}
+			return io.EOF
+		}
+		row = rows.records[rows.pos-1]
+	}
 
-	row := rows.records[rows.pos-1]
 	for idx, v := range row {
-		dest[idx] = v
+		dest[idx] = rows.convert(idx, v)
 	}
 
 	return nil
 }
+
+// readRowsResponse reads a sql_fast response up to the records of a Result_Table, which
+// are then decoded one at a time by vRows.Next. If the returned decoder is nil, the response
+// was read completely and the records, if any, are buffered in the response.
+func readRowsResponse(resp *http.Response) (*vFastSQLResponse, *json.Decoder, error) {
+	dec := json.NewDecoder(resp.Body)
+	dec.UseNumber()
+
+	var response vFastSQLResponse
+	if tok, err := dec.Token(); err != nil {
+		return nil, nil, err
+	} else if tok != json.Delim('{') {
+		return nil, nil, fmt.Errorf("unexpected JSON token: %v", tok)
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		key, _ := tok.(string)
+		switch strings.ToLower(key) {
+		case "name":
+			err = dec.Decode(&response.Name)
+		case "fields":
+			err = dec.Decode(&response.Fields)
+		case "affectedrows":
+			err = dec.Decode(&response.AffectedRows)
+		case "error":
+			err = dec.Decode(&response.Error)
+		case "records":
+			// Stream the records if we already know it's a table, otherwise buffer them
+			if resp.StatusCode == http.StatusOK && response.Name == "Result_Table" && response.Fields != nil && response.Error == "" {
+				if tok, err := dec.Token(); err != nil {
+					return nil, nil, err
+				} else if tok != json.Delim('[') {
+					return nil, nil, fmt.Errorf("unexpected JSON token: %v", tok)
+				}
+				return &response, dec, nil
+			}
+			err = dec.Decode(&response.Records)
+		default:
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return &response, nil, nil
+}
+
+// convert turns a decoded JSON value into a driver.Value.
+func (rows *vRows) convert(index int, v any) driver.Value {
+	switch v := v.(type) {
+	case json.Number:
+		return convertNumber(v, rows.columnType(index).kind)
+	case string:
+		if rows.conn == nil {
+			return v
+		}
+		// Only hex strings can be BLOBs, other strings don't need the column type
+		if isBlobLiteral(v) && rows.columnType(index).kind == kindBinary {
+			return decodeBlob(v)
+		}
+		if rows.conn.parseTime {
+			if t, ok := rows.conn.parseTimeValue(v, rows.columnType(index).kind); ok {
+				return t
+			}
+		}
+	case []any, map[string]any:
+		return convertJSON(v)
+	}
+	return v
+}
+
+// convertNumber converts a number by the kind of its column: int64 for integers, float64 for
+// floating-point and an exact string for DECIMAL and MONEY values. If the kind is unknown,
+// the literal decides, integers that don't fit into an int64 are returned as strings.
+func convertNumber(n json.Number, kind typeKind) any {
+	switch kind {
+	case kindDecimal:
+		return n.String()
+	case kindFloat:
+		if f, err := n.Float64(); err == nil {
+			return f
+		}
+		return n.String()
+	case kindInt:
+		if i, err := n.Int64(); err == nil {
+			return i
+		}
+		return n.String()
+	}
+
+	if isIntegerLiteral(n) {
+		if i, err := n.Int64(); err == nil {
+			return i
+		}
+		return n.String()
+	}
+	if f, err := n.Float64(); err == nil {
+		return f
+	}
+	return n.String()
+}
+
+// convertJSON converts the numbers within arrays and objects, i.e. of ARRAY fields.
+func convertJSON(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		return convertNumber(v, kindUnknown)
+	case []any:
+		for i := range v {
+			v[i] = convertJSON(v[i])
+		}
+	case map[string]any:
+		for k := range v {
+			v[k] = convertJSON(v[k])
+		}
+	}
+	return v
+}
+
+func isIntegerLiteral(n json.Number) bool {
+	return !strings.ContainsAny(string(n), ".eE")
+}
+
+func (rows *vRows) ColumnTypeDatabaseTypeName(index int) string {
+	return rows.columnType(index).typeName
+}
+
+func (rows *vRows) ColumnTypeScanType(index int) reflect.Type {
+	ct := rows.columnType(index)
+	if rows.conn != nil && rows.conn.parseTime {
+		switch ct.kind {
+		case kindDate, kindTime, kindDateTime:
+			return scanTypeTime
+		}
+	}
+	return ct.scanType()
+}
+
+func (rows *vRows) ColumnTypeNullable(index int) (nullable, ok bool) {
+	ct := rows.columnType(index)
+	return ct.nullable, ct.hasNullable
+}
+
+func (rows *vRows) ColumnTypeLength(index int) (length int64, ok bool) {
+	ct := rows.columnType(index)
+	return ct.length, ct.length > 0
+}
+
+func (rows *vRows) ColumnTypePrecisionScale(index int) (precision, scale int64, ok bool) {
+	ct := rows.columnType(index)
+	return ct.precision, ct.scale, ct.kind == kindDecimal && ct.precision > 0
+}
+
+// columnType returns the type of a result field, the zero value if it is unknown.
+func (rows *vRows) columnType(index int) columnType {
+	if index < 0 || index >= len(rows.types) {
+		return columnType{}
+	}
+	return rows.types[index]
+}
@@ -0,0 +1,224 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vdriver
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// The REST API executes one statement per request, so scripts of statements separated by
+// semicolons are split and executed one after another on the same session.
+
+type statement struct {
+	query string
+	args  []driver.NamedValue
+}
+
+// splitScript binds named arguments and splits query into its statements. The placeholders
+// of a script refer to all arguments, they are renumbered for the arguments of each statement.
+// A single statement is returned unchanged.
+func splitScript(query string, args []driver.NamedValue, vendor Vendor) ([]statement, error) {
+	query, err := bindNamed(query, args, vendor)
+	if err != nil {
+		return nil, err
+	}
+
+	// The names are bound now, so the arguments are positional
+	if len(args) > 0 {
+		positional := make([]driver.NamedValue, len(args))
+		for i, arg := range args {
+			positional[i] = driver.NamedValue{Ordinal: i + 1, Value: arg.Value}
+		}
+		args = positional
+	}
+
+	// Statements start at their first token that isn't a comment or whitespace
+	var spans []string
+	var blocks blockDepth
+	start := -1
+	l := newLexer(query, vendor)
+	for {
+		tok, ok := l.next()
+		if !ok {
+			break
+		}
+		switch tok.kind {
+		case tokSemicolon:
+			if !blocks.endsStatement() {
+				continue
+			}
+			if start >= 0 {
+				spans = append(spans, query[start:tok.pos])
+			}
+			start = -1
+		case tokComment:
+		default:
+			if tok.kind == tokText {
+				blocks.text(tok.text)
+			}
+			text := strings.TrimLeft(tok.text, " \t\r\n")
+			if start < 0 && text != "" {
+				start = tok.pos + len(tok.text) - len(text)
+			}
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, query[start:])
+	}
+
+	if len(spans) <= 1 {
+		return []statement{{query: query, args: args}}, nil
+	}
+
+	stmts := make([]statement, len(spans))
+	next := 0 // Next argument of a ? placeholder
+	for i, span := range spans {
+		stmts[i], next, err = bindStatement(strings.TrimSpace(span), args, next, vendor)
+		if err != nil {
+			return nil, fmt.Errorf("statement %d: %w", i+1, err)
+		}
+	}
+	return stmts, nil
+}
+
+// blockDepth tracks the BEGIN … END and CASE … END blocks of a statement, i.e. the bodies of
+// triggers and procedures, whose semicolons don't end the statement.
+type blockDepth struct {
+	depth      int
+	words      int  // Words of the current statement
+	create     bool // The statement starts with CREATE
+	routine    bool // The statement creates a trigger, procedure or function, which have a body
+	pendingEnd bool // END was the last word, it may be followed by IF, LOOP, …
+}
+
+// text counts the words of a text token.
+func (b *blockDepth) text(text string) {
+	for i := 0; i < len(text); {
+		if !isIdentChar(text[i]) {
+			i++
+			continue
+		}
+		end := skipIdent(text, i)
+		// Qualified names like t.end are no keywords
+		if i == 0 || text[i-1] != '.' {
+			b.word(strings.ToUpper(text[i:end]))
+		}
+		i = end
+	}
+}
+
+func (b *blockDepth) word(w string) {
+	b.words++
+	if b.pendingEnd {
+		b.pendingEnd = false
+		switch w {
+		case "IF", "LOOP", "WHILE", "REPEAT", "FOR":
+			return // These statements don't open a block
+		case "CASE":
+			b.depth--
+			return
+		}
+		b.depth--
+	}
+
+	switch w {
+	case "CREATE":
+		b.create = b.create || b.words == 1
+	case "TRIGGER", "PROCEDURE", "FUNCTION":
+		b.routine = b.routine || b.create && b.depth == 0
+	case "BEGIN":
+		// Only the bodies of routines are blocks, a leading BEGIN starts a transaction
+		// (BEGIN TRANSACTION) and others are identifiers, i.e. a field named begin
+		if b.depth > 0 || b.routine {
+			b.depth++
+		}
+	case "CASE":
+		b.depth++
+	case "END":
+		// END at the top level is END TRANSACTION
+		if b.depth > 0 {
+			b.pendingEnd = true
+		}
+	}
+}
+
+// endsStatement reports whether a semicolon ends the statement, which it doesn't within a block.
+func (b *blockDepth) endsStatement() bool {
+	if b.pendingEnd {
+		b.pendingEnd = false
+		b.depth--
+	}
+	b.words = 0
+	if b.depth == 0 {
+		b.create, b.routine = false, false
+	}
+	return b.depth == 0
+}
+
+// bindStatement returns the statement with the arguments it refers to. ? placeholders take
+// the arguments in order, starting at next, :N placeholders are renumbered.
+func bindStatement(query string, args []driver.NamedValue, next int, vendor Vendor) (statement, int, error) {
+	stmt := statement{query: query}
+	var sb strings.Builder
+	ordinals := make(map[int]int) // Ordinal in the script to ordinal in the statement
+	positional := false
+
+	l := newLexer(query, vendor)
+	for {
+		tok, ok := l.next()
+		if !ok {
+			break
+		}
+
+		switch tok.kind {
+		case tokPositional:
+			if len(ordinals) > 0 {
+				return stmt, next, fmt.Errorf("cannot mix ? and :N placeholders")
+			}
+			if next >= len(args) {
+				return stmt, next, fmt.Errorf("not enough arguments for the ? placeholders")
+			}
+			positional = true
+			stmt.args = append(stmt.args, driver.NamedValue{Ordinal: len(stmt.args) + 1, Value: args[next].Value})
+			next++
+			sb.WriteString(tok.text)
+		case tokOrdinal:
+			if positional {
+				return stmt, next, fmt.Errorf("cannot mix ? and :N placeholders")
+			}
+			n, err := strconv.Atoi(tok.text[1:])
+			if err != nil || n < 1 || n > len(args) {
+				return stmt, next, fmt.Errorf("placeholder %s has no argument", tok.text)
+			}
+			ordinal, ok := ordinals[n]
+			if !ok {
+				stmt.args = append(stmt.args, driver.NamedValue{Ordinal: len(stmt.args) + 1, Value: args[n-1].Value})
+				ordinal = len(stmt.args)
+				ordinals[n] = ordinal
+			}
+			sb.WriteString(":" + strconv.Itoa(ordinal))
+		default:
+			sb.WriteString(tok.text)
+		}
+	}
+
+	stmt.query = sb.String()
+	return stmt, next, nil
+}
+
+// scriptError adds the number of the failed statement to err. If earlier statements were
+// executed, the connection is marked as bad instead of returning driver.ErrBadConn, so
+// database/sql doesn't execute them again.
+func (c *vConn) scriptError(index int, err error) error {
+	if index > 0 && errors.Is(err, driver.ErrBadConn) {
+		c.bad = true
+		return fmt.Errorf("statement %d: %v", index+1, err)
+	}
+	return fmt.Errorf("statement %d: %w", index+1, err)
+}
@@ -0,0 +1,247 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vdriver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Session describes the REST session of a connection.
+type Session struct {
+	ID        string
+	Vendor    Vendor
+	Database  string
+	Created   time.Time // When the session was created, or last recreated
+	LastUsed  time.Time // When the server last answered a request of the session
+	Refreshes int       // How often the session was recreated after it expired
+}
+
+// SessionInfo returns the REST session of conn, which must be a connection of this driver.
+func SessionInfo(conn *sql.Conn) (Session, error) {
+	var session Session
+	err := conn.Raw(func(driverConn any) error {
+		c, ok := driverConn.(*vConn)
+		if !ok {
+			return fmt.Errorf("not a Valentina connection: %T", driverConn)
+		}
+		session = Session{
+			ID:        c.sessionID,
+			Vendor:    Vendor(c.vendor),
+			Database:  c.database,
+			Created:   c.created,
+			LastUsed:  c.lastUsed,
+			Refreshes: c.refreshes,
+		}
+		return nil
+	})
+	return session, err
+}
+
+// DriverStats are counters of all connections of the driver, i.e. to watch the REST
+// connections a license allows.
+type DriverStats struct {
+	SessionsCreated int64 // Successful logins
+	SessionsClosed  int64 // Sessions removed by closing the connection
+	SessionsExpired int64 // Sessions the server removed, i.e. after they were idle too long
+	OpenSessions    int64 // Sessions that were neither closed nor expired
+	Refreshes       int64 // Sessions recreated after they expired
+	Requests        int64 // Requests sent to the REST API, including logins
+}
+
+var stats struct {
+	sessionsCreated atomic.Int64
+	sessionsClosed  atomic.Int64
+	sessionsExpired atomic.Int64
+	refreshes       atomic.Int64
+	requests        atomic.Int64
+}
+
+// Stats returns the counters of all connections since the program started.
+func Stats() DriverStats {
+	s := DriverStats{
+		SessionsCreated: stats.sessionsCreated.Load(),
+		SessionsClosed:  stats.sessionsClosed.Load(),
+		SessionsExpired: stats.sessionsExpired.Load(),
+		Refreshes:       stats.refreshes.Load(),
+		Requests:        stats.requests.Load(),
+	}
+	s.OpenSessions = s.SessionsCreated - s.SessionsClosed - s.SessionsExpired
+	return s
+}
+
+// markExpired counts the session of the connection as expired, once.
+func (c *vConn) markExpired() {
+	if c.sessionID != "" && !c.expired {
+		c.expired = true
+		stats.sessionsExpired.Add(1)
+		c.log(context.Background(), slog.LevelInfo, "valentina session expired", slog.Duration("age", time.Since(c.created)))
+	}
+}
+
+// defaultSessionTimeout is the idle time after which the server removes a REST session,
+// unless configured otherwise.
+const defaultSessionTimeout = 20 * time.Minute
+
+// initSession sets the properties of a new session.
+func (c *vConn) initSession(ctx context.Context) error {
+	// Set the date format for this connection. This is required for the time.Time type.
+	// Both proerties are only visible to the current connection and not persisted in the database,
+	// see https://valentina-db.com/docs/dokuwiki/v15/doku.php?id=valentina:vcomponents:vkernel:database:datetime_format&s[]=kymd
+	err := c.setDatabasePropertyString(ctx, "DateTimeFormat", c.dateFormat)
+	if err != nil {
+		return fmt.Errorf("cannot set database DateTimeFormat property: %w", err)
+	}
+
+	err = c.setDatabasePropertyString(ctx, "DateSeparator", kDateSeparater)
+	if err != nil {
+		return fmt.Errorf("cannot set database DateSeparator property: %w", err)
+	}
+
+	return nil
+}
+
+// idleExpiring reports whether the session was idle so long that the server may remove it
+// before the next request. A margin of a tenth of the timeout, at most a minute, covers
+// clock drift and the time until the next request.
+func (c *vConn) idleExpiring() bool {
+	margin := min(c.sessionTimeout/10, time.Minute)
+	return time.Since(c.lastUsed) >= c.sessionTimeout-margin
+}
+
+// ResetSession is called by database/sql before a connection is reused. It logs in again
+// if the session expired or is about to, so the next query doesn't fail.
+func (c *vConn) ResetSession(ctx context.Context) error {
+	if c.bad {
+		return driver.ErrBadConn
+	}
+	if !c.expired && !c.idleExpiring() {
+		return nil
+	}
+
+	if err := c.refreshSession(ctx); err != nil {
+		c.bad = true
+		return driver.ErrBadConn
+	}
+	return nil
+}
+
+// IsValid is called by database/sql before a connection is returned to the pool.
+func (c *vConn) IsValid() bool {
+	return !c.bad
+}
+
+// refreshSession replaces the session of the connection with a new one.
+func (c *vConn) refreshSession(ctx context.Context) error {
+	idle := time.Since(c.lastUsed)
+	if !c.expired && c.sessionID != "" {
+		// Free the old session for the license, it may still exist
+		_ = c.deleteSession(ctx)
+	}
+
+	c.sessionID = ""
+	if err := c.createSession(ctx); err != nil {
+		c.log(ctx, slog.LevelError, "valentina session refresh failed", slog.String("error", err.Error()))
+		return err
+	}
+	if err := c.initSession(ctx); err != nil {
+		c.log(ctx, slog.LevelError, "valentina session refresh failed", slog.String("error", err.Error()))
+		return err
+	}
+
+	c.refreshes++
+	stats.refreshes.Add(1)
+	c.log(ctx, slog.LevelInfo, "valentina session refreshed", slog.Duration("idle", idle), slog.Int("refreshes", c.refreshes))
+	return nil
+}
+
+// relogin re-creates an expired session in place, if enabled with the relogin option. It reports
+// whether the statement that failed with err may be executed again, which is only the case for
+// read-only statements. Other statements fail with an error that no longer matches
+// driver.ErrBadConn, as the connection can be used again.
+// Within a transaction the session is not replaced, as the new one wouldn't have the
+// transaction: the connection is marked as bad instead.
+func (c *vConn) relogin(ctx context.Context, query string, err error) (replay bool, _ error) {
+	var verr *Error
+	if !c.reloginEnabled || !errors.As(err, &verr) || !verr.Is(ErrSessionExpired) || verr.recreated {
+		return false, err
+	}
+	if c.inTx {
+		c.bad = true
+		return false, err
+	}
+
+	if rerr := c.refreshSession(ctx); rerr != nil {
+		c.bad = true
+		return false, err
+	}
+	if isReadOnly(query, Vendor(c.vendor)) {
+		return true, nil
+	}
+
+	verr.recreated = true
+	return false, err
+}
+
+// isReadOnly reports whether query is a SELECT, SHOW or GET PROPERTY statement. SELECTs
+// that store their result (SELECT … INTO), contain a write statement, lock their records
+// (FOR UPDATE) or advance a sequence are not read-only. Side effects of other functions
+// can't be recognized.
+func isReadOnly(query string, vendor Vendor) bool {
+	var words []string
+	l := newLexer(query, vendor)
+	for {
+		tok, ok := l.next()
+		if !ok {
+			break
+		}
+		if tok.kind == tokText {
+			words = appendWords(words, tok.text)
+		}
+	}
+	if len(words) == 0 {
+		return false
+	}
+
+	switch words[0] {
+	case "SHOW":
+		return true
+	case "GET":
+		return len(words) > 1 && words[1] == "PROPERTY"
+	case "SELECT":
+		for _, w := range words[1:] {
+			switch w {
+			case "INTO", "INSERT", "UPDATE", "DELETE", "CREATE", "DROP", "ALTER", "NEXTVAL", "SETVAL":
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// appendWords appends the upper-cased words of a text token. Qualified names like t.into
+// are no keywords.
+func appendWords(words []string, text string) []string {
+	for i := 0; i < len(text); {
+		if !isIdentChar(text[i]) {
+			i++
+			continue
+		}
+		end := skipIdent(text, i)
+		if i == 0 || text[i-1] != '.' {
+			words = append(words, strings.ToUpper(text[i:end]))
+		}
+		i = end
+	}
+	return words
+}
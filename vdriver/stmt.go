@@ -10,8 +10,9 @@ import (
 )
 
 type vStmt struct {
-	conn  *vConn
-	query string
+	conn     *vConn
+	query    string
+	numInput int
 }
 
 func (s vStmt) Close() error {
@@ -19,15 +20,23 @@ func (s vStmt) Close() error {
 }
 
 func (s vStmt) NumInput() int {
-	return -1 // TODO: at the moment we don't know how many placeholders are in the query
+	return s.numInput
 }
 
 func (s vStmt) Exec(args []driver.Value) (driver.Result, error) {
-	return s.conn.ExecContext(context.Background(), s.query, valuesToNamedValues(args))
+	return s.ExecContext(context.Background(), valuesToNamedValues(args))
 }
 
 func (s vStmt) Query(args []driver.Value) (driver.Rows, error) {
-	return s.conn.QueryContext(context.Background(), s.query, valuesToNamedValues(args))
+	return s.QueryContext(context.Background(), valuesToNamedValues(args))
+}
+
+func (s vStmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	return s.conn.ExecContext(ctx, s.query, args)
+}
+
+func (s vStmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	return s.conn.QueryContext(ctx, s.query, args)
 }
 
 func valuesToNamedValues(args []driver.Value) []driver.NamedValue {
@@ -35,7 +44,7 @@ func valuesToNamedValues(args []driver.Value) []driver.NamedValue {
 	for i, arg := range args {
 		namedArgs = append(namedArgs, driver.NamedValue{
 			Name:    "",
-			Ordinal: i,
+			Ordinal: i + 1,
 			Value:   arg,
 		})
 	}
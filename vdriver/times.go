@@ -0,0 +1,62 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vdriver
+
+import (
+	"strings"
+	"time"
+)
+
+// Valentina returns date and time values as strings in the DateTimeFormat of the session,
+// with a colon before the milliseconds, i.e. "2025-01-02 17:56:39:400".
+
+const clockLayout = "15:04:05.999999999"
+
+// parseTimeValue parses a DATE, TIME or DATETIME value, ok is false if s doesn't match the layout
+// or the kind isn't a date or time. Values of unknown kind may be text, so they aren't parsed.
+func (c *vConn) parseTimeValue(s string, kind typeKind) (t time.Time, ok bool) {
+	loc := c.loc
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	var layout string
+	switch kind {
+	case kindDate:
+		layout = c.dateLayout()
+	case kindTime:
+		layout = clockLayout
+		s = normalizeClock(s)
+	case kindDateTime:
+		date, clock, found := strings.Cut(s, " ")
+		if !found {
+			date, clock, found = strings.Cut(s, "T")
+		}
+		if !found {
+			// A DATETIME without a time part
+			layout = c.dateLayout()
+			break
+		}
+		layout = c.dateLayout() + " " + clockLayout
+		s = date + " " + normalizeClock(clock)
+	default:
+		return time.Time{}, false
+	}
+
+	t, err := time.ParseInLocation(layout, s, loc)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// normalizeClock replaces the colon before the milliseconds with a dot.
+func normalizeClock(s string) string {
+	if strings.Count(s, ":") == 3 {
+		i := strings.LastIndexByte(s, ':')
+		return s[:i] + "." + s[i+1:]
+	}
+	return s
+}
@@ -0,0 +1,140 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vdriver
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const userAgent = "valentina-go"
+
+var errResponseTooLarge = errors.New("response exceeds maxResponseBytes")
+
+var (
+	tlsConfigLock     sync.RWMutex
+	tlsConfigRegistry = make(map[string]*tls.Config)
+)
+
+// RegisterTLSConfig registers a custom TLS configuration, which can be used with the
+// DSN parameter tls=name. Use it to trust a private CA, pin a server certificate or
+// authenticate with client certificates.
+//
+//	rootCertPool := x509.NewCertPool()
+//	rootCertPool.AppendCertsFromPEM(pem)
+//	vdriver.RegisterTLSConfig("custom", &tls.Config{RootCAs: rootCertPool})
+//	db, err := sql.Open("valentina", "https://sa:sa@localhost:19999/testdb?tls=custom")
+func RegisterTLSConfig(name string, config *tls.Config) error {
+	switch name {
+	case "", "true", "false", "skip-verify":
+		return fmt.Errorf("TLS configuration name %q is reserved", name)
+	}
+	if config == nil {
+		return fmt.Errorf("TLS configuration %q is nil", name)
+	}
+
+	tlsConfigLock.Lock()
+	defer tlsConfigLock.Unlock()
+	tlsConfigRegistry[name] = config.Clone()
+	return nil
+}
+
+// DeregisterTLSConfig removes a TLS configuration registered with RegisterTLSConfig.
+func DeregisterTLSConfig(name string) {
+	tlsConfigLock.Lock()
+	defer tlsConfigLock.Unlock()
+	delete(tlsConfigRegistry, name)
+}
+
+func registeredTLSConfig(name string) (*tls.Config, bool) {
+	tlsConfigLock.RLock()
+	defer tlsConfigLock.RUnlock()
+	config, ok := tlsConfigRegistry[name]
+	if !ok {
+		return nil, false
+	}
+	return config.Clone(), true
+}
+
+// newTransport returns the HTTP transport for the timeouts and TLS settings of the configuration.
+func (cfg Config) newTransport() (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if cfg.Timeout > 0 {
+		dialer := net.Dialer{
+			Timeout:   cfg.Timeout,
+			KeepAlive: 30 * time.Second,
+		}
+		transport.DialContext = dialer.DialContext
+		transport.TLSHandshakeTimeout = cfg.Timeout
+	}
+	transport.ResponseHeaderTimeout = cfg.ReadTimeout
+
+	if cfg.useTLS() {
+		tlsConfig, err := cfg.tlsConfig()
+		if err != nil {
+			return nil, err
+		}
+		transport.TLSClientConfig = tlsConfig
+	}
+
+	return transport, nil
+}
+
+func (cfg Config) tlsConfig() (*tls.Config, error) {
+	if cfg.TLSConfig != nil {
+		return cfg.TLSConfig.Clone(), nil
+	}
+
+	switch cfg.TLS {
+	case "":
+		return nil, nil // Use the default configuration
+	case "skip-verify":
+		return &tls.Config{InsecureSkipVerify: true}, nil
+	}
+
+	if config, ok := registeredTLSConfig(cfg.TLS); ok {
+		return config, nil
+	}
+	return nil, fmt.Errorf("unknown TLS configuration %q, see RegisterTLSConfig", cfg.TLS)
+}
+
+// userAgent returns the User-Agent header, including the application name.
+func (cfg Config) userAgent() string {
+	if cfg.ApplicationName == "" {
+		return userAgent
+	}
+	return userAgent + " (" + cfg.ApplicationName + ")"
+}
+
+// limitedBody fails with errResponseTooLarge once a response body exceeds maxResponseBytes.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		// Only fail if there actually is more data
+		var one [1]byte
+		if n, err := b.ReadCloser.Read(one[:]); n == 0 {
+			return 0, err
+		}
+		return 0, errResponseTooLarge
+	}
+
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	return n, err
+}
@@ -4,17 +4,85 @@
 
 package vdriver
 
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+)
+
 // Transactions are not supported in Valentina DB.
-// TODO: Other engines might support transactions.
+// The SQLite and DuckDB engines support them, the statements are sent through sql_fast.
 
 type vTx struct {
-	conn *vConn
+	conn     *vConn
+	readOnly bool
+}
+
+func (c *vConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	isolation := sql.IsolationLevel(opts.Isolation)
+
+	switch Vendor(c.vendor) {
+	case VendorSQLite:
+		// SQLite transactions are always serializable
+		if isolation != sql.LevelDefault && isolation != sql.LevelSerializable {
+			return nil, fmt.Errorf("%w: isolation level %s on %s", ErrNotSupported, isolation, c.vendor)
+		}
+	case VendorDuckDB:
+		// DuckDB uses MVCC with snapshot isolation and has no read-only transactions
+		if isolation != sql.LevelDefault && isolation != sql.LevelSnapshot {
+			return nil, fmt.Errorf("%w: isolation level %s on %s", ErrNotSupported, isolation, c.vendor)
+		}
+		if opts.ReadOnly {
+			return nil, fmt.Errorf("%w: read-only transactions on %s", ErrNotSupported, c.vendor)
+		}
+	default:
+		return nil, ErrTxNotImplemented
+	}
+
+	if _, err := c.ExecContext(ctx, "BEGIN TRANSACTION", nil); err != nil {
+		return nil, fmt.Errorf("cannot begin transaction: %w", err)
+	}
+
+	c.inTx = true
+	tx := vTx{
+		conn:     c,
+		readOnly: opts.ReadOnly,
+	}
+
+	if tx.readOnly {
+		// SQLite has no read-only transactions, but query_only rejects all changes on this connection
+		if _, err := c.ExecContext(ctx, "PRAGMA query_only = ON", nil); err != nil {
+			_, _ = c.ExecContext(ctx, "ROLLBACK", nil)
+			c.inTx = false
+			return nil, fmt.Errorf("cannot begin read-only transaction: %w", err)
+		}
+	}
+
+	return tx, nil
 }
 
 func (tx vTx) Commit() error {
-	return ErrTxNotImplemented
+	return tx.end("COMMIT")
 }
 
 func (tx vTx) Rollback() error {
-	return ErrTxNotImplemented
+	return tx.end("ROLLBACK")
+}
+
+func (tx vTx) end(stmt string) error {
+	ctx := context.Background()
+	defer func() { tx.conn.inTx = false }()
+
+	_, err := tx.conn.ExecContext(ctx, stmt, nil)
+	if tx.readOnly {
+		if _, resetErr := tx.conn.ExecContext(ctx, "PRAGMA query_only = OFF", nil); resetErr != nil && err == nil {
+			err = resetErr
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("cannot %s transaction: %w", stmt, err)
+	}
+
+	return nil
 }
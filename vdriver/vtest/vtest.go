@@ -0,0 +1,420 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package vtest provides an in-process stand-in for the Valentina Server REST API,
+// so the vdriver package can be tested without a running (and licensed) Valentina Server.
+//
+// The server implements the three endpoints used by the driver:
+//
+//	POST   /rest                      creates a session and returns a sessionID cookie
+//	DELETE /rest/session_id           removes the session
+//	POST   /rest/session_id/sql_fast  executes a query
+//
+// Queries are answered with scripted responses, see Server.Handle and Server.HandleFunc.
+package vtest
+
+import (
+	"crypto/md5"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strconv"
+	"sync"
+)
+
+const (
+	// Version is returned for "SELECT version()" unless scripted otherwise.
+	Version = "15.1.2"
+
+	// Messages returned by the Valentina REST API.
+	MsgSessionNotFound = "Session does not exist"
+	MsgNoResult        = "neither cursor nor affectedRows"
+	MsgAuthFailed      = "Wrong user name or password"
+)
+
+var (
+	reSetProperty = regexp.MustCompile(`(?i)^\s*SET\s+PROPERTY\s+(\w+)\s+OF\s+DATABASE\s+TO\s+(?:\?|:1)\s*$`)
+	reGetProperty = regexp.MustCompile(`(?i)^\s*GET\s+PROPERTY\s+(\w+)\s+OF\s+DATABASE\s*$`)
+	reVersion     = regexp.MustCompile(`(?i)^\s*SELECT\s+version\(\)\s*$`)
+)
+
+// Request is a sql_fast request as received by the server.
+type Request struct {
+	SessionID string
+	Vendor    string
+	Database  string
+	Query     string
+	Params    []any
+}
+
+// Response is a scripted answer to a sql_fast request.
+// Status defaults to 200, or 400 if Error is set.
+type Response struct {
+	Status       int
+	Name         string
+	Fields       []string
+	Records      [][]any
+	AffectedRows int64
+	Error        string
+}
+
+// Table returns a Result_Table response with the given fields and records.
+func Table(fields []string, records ...[]any) Response {
+	if records == nil {
+		records = [][]any{}
+	}
+	return Response{
+		Name:    "Result_Table",
+		Fields:  fields,
+		Records: records,
+	}
+}
+
+// Affected returns a response for a statement that affected n records.
+func Affected(n int64) Response {
+	return Response{AffectedRows: n}
+}
+
+// NoResult returns the response for a statement that has neither rows nor affected records,
+// like "SET PROPERTY ..." or "BEGIN".
+func NoResult() Response {
+	return Response{Error: MsgNoResult}
+}
+
+// Failure returns an error response with the given HTTP status.
+func Failure(status int, msg string) Response {
+	return Response{Status: status, Error: msg}
+}
+
+// HandlerFunc answers sql_fast requests that have no scripted response.
+// If ok is false, the server falls back to its built-in responses.
+type HandlerFunc func(req Request) (resp Response, ok bool)
+
+type session struct {
+	user       string
+	properties map[string]any
+}
+
+// Server is a fake Valentina REST server. Create it with NewServer.
+type Server struct {
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	users    map[string]string
+	sessions map[string]*session
+	scripts  map[string][]Response
+	handler  HandlerFunc
+	requests []Request
+	logins   int
+	nextID   int
+}
+
+// NewServer starts a fake server that accepts the user "sa" with password "sa".
+// Callers should call Close when finished.
+func NewServer() *Server {
+	s := newServer()
+	s.srv = httptest.NewServer(s.mux())
+	return s
+}
+
+// NewTLSServer starts a fake server using HTTPS with a self-signed certificate, see Certificate.
+func NewTLSServer() *Server {
+	s := newServer()
+	s.srv = httptest.NewTLSServer(s.mux())
+	return s
+}
+
+func newServer() *Server {
+	return &Server{
+		users:    map[string]string{"sa": hashPassword("sa")},
+		sessions: map[string]*session{},
+		scripts:  map[string][]Response{},
+	}
+}
+
+func (s *Server) mux() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /rest", s.handleLogin)
+	mux.HandleFunc("DELETE /rest/session_id", s.handleLogout)
+	mux.HandleFunc("POST /rest/session_id/sql_fast", s.handleSQLFast)
+	return mux
+}
+
+// Close shuts down the server.
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Certificate returns the certificate of a server started with NewTLSServer, or nil.
+func (s *Server) Certificate() *x509.Certificate {
+	return s.srv.Certificate()
+}
+
+// URL returns the base URL of the server, i.e. http://127.0.0.1:port
+func (s *Server) URL() string {
+	return s.srv.URL
+}
+
+// Host returns the host the server listens on.
+func (s *Server) Host() string {
+	host, _, _ := net.SplitHostPort(s.srv.Listener.Addr().String())
+	return host
+}
+
+// Port returns the port the server listens on.
+func (s *Server) Port() int {
+	_, port, _ := net.SplitHostPort(s.srv.Listener.Addr().String())
+	p, _ := strconv.Atoi(port)
+	return p
+}
+
+// DSN returns a connection string for the user "sa" and the given database.
+func (s *Server) DSN(db string) string {
+	scheme := "http"
+	if s.srv.TLS != nil {
+		scheme = "https"
+	}
+
+	u := url.URL{
+		Scheme: scheme,
+		User:   url.UserPassword("sa", "sa"),
+		Host:   s.srv.Listener.Addr().String(),
+		Path:   "/" + db,
+	}
+	return u.String()
+}
+
+// AddUser adds a user or changes the password of an existing one.
+func (s *Server) AddUser(user, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.users[user] = hashPassword(password)
+}
+
+// Handle scripts the responses for a query. Responses are returned in order,
+// the last one is repeated for all further requests.
+func (s *Server) Handle(query string, responses ...Response) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scripts[query] = responses
+}
+
+// HandleFunc sets the handler for queries that have no scripted response.
+func (s *Server) HandleFunc(f HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handler = f
+}
+
+// ExpireSessions drops all sessions, as the server does after its idle timeout.
+func (s *Server) ExpireSessions() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clear(s.sessions)
+}
+
+// Sessions returns the IDs of all open sessions.
+func (s *Server) Sessions() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]string, 0, len(s.sessions))
+	for id := range s.sessions {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// Logins returns the number of successfully created sessions.
+func (s *Server) Logins() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.logins
+}
+
+// Property returns a database property set by the given session.
+func (s *Server) Property(sessionID, name string) (any, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.sessions[sessionID]
+	if !ok {
+		return nil, false
+	}
+	v, ok := sess.properties[name]
+	return v, ok
+}
+
+// Requests returns all sql_fast requests received so far.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		User     string `json:"user"`
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		writeJSON(w, http.StatusBadRequest, Response{Error: err.Error()})
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hash, ok := s.users[payload.User]
+	if !ok || hash != payload.Password {
+		writeJSON(w, http.StatusUnauthorized, Response{Error: MsgAuthFailed})
+		return
+	}
+
+	s.nextID++
+	s.logins++
+	id := fmt.Sprintf("vtest-%d", s.nextID)
+	s.sessions[id] = &session{
+		user:       payload.User,
+		properties: map[string]any{},
+	}
+
+	// The driver expects exactly "sessionID=<id>", without any cookie attributes
+	w.Header().Set("Set-Cookie", "sessionID="+id)
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (s *Server) handleLogout(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := sessionID(r)
+	if _, ok := s.sessions[id]; !ok {
+		writeJSON(w, http.StatusNotFound, Response{Error: MsgSessionNotFound})
+		return
+	}
+	delete(s.sessions, id)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleSQLFast(w http.ResponseWriter, r *http.Request) {
+	var payload struct {
+		Vendor   string `json:"vendor"`
+		Database string `json:"database"`
+		Query    string `json:"Query"`
+		Params   []any  `json:"Params"`
+	}
+	dec := json.NewDecoder(r.Body)
+	dec.UseNumber()
+	if err := dec.Decode(&payload); err != nil {
+		writeJSON(w, http.StatusBadRequest, Response{Error: err.Error()})
+		return
+	}
+
+	req := Request{
+		SessionID: sessionID(r),
+		Vendor:    payload.Vendor,
+		Database:  payload.Database,
+		Query:     payload.Query,
+		Params:    payload.Params,
+	}
+
+	s.mu.Lock()
+	sess, ok := s.sessions[req.SessionID]
+	if !ok {
+		s.mu.Unlock()
+		writeJSON(w, http.StatusNotFound, Response{Error: MsgSessionNotFound})
+		return
+	}
+	s.requests = append(s.requests, req)
+	resp, scripted := s.scripted(req.Query)
+	handler := s.handler
+	s.mu.Unlock()
+
+	if !scripted && handler != nil {
+		resp, scripted = handler(req)
+	}
+	if !scripted {
+		resp = s.builtin(sess, req)
+	}
+
+	status := resp.Status
+	if status == 0 {
+		status = http.StatusOK
+		if resp.Error != "" {
+			status = http.StatusBadRequest
+		}
+	}
+	writeJSON(w, status, resp)
+}
+
+// scripted returns the next scripted response for query, s.mu must be held.
+func (s *Server) scripted(query string) (Response, bool) {
+	responses, ok := s.scripts[query]
+	if !ok || len(responses) == 0 {
+		return Response{}, false
+	}
+	if len(responses) > 1 {
+		s.scripts[query] = responses[1:]
+	}
+	return responses[0], true
+}
+
+// builtin answers the statements the driver sends on its own.
+func (s *Server) builtin(sess *session, req Request) Response {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if m := reSetProperty.FindStringSubmatch(req.Query); m != nil && len(req.Params) == 1 {
+		sess.properties[m[1]] = req.Params[0]
+		return NoResult()
+	}
+	if m := reGetProperty.FindStringSubmatch(req.Query); m != nil {
+		v, ok := sess.properties[m[1]]
+		if !ok {
+			return Failure(http.StatusBadRequest, "Unknown property: "+m[1])
+		}
+		return Table([]string{m[1]}, []any{v})
+	}
+	if reVersion.MatchString(req.Query) {
+		return Table([]string{"version()"}, []any{Version})
+	}
+
+	return Failure(http.StatusBadRequest, "vtest: no response scripted for query: "+req.Query)
+}
+
+func sessionID(r *http.Request) string {
+	cookie, err := r.Cookie("sessionID")
+	if err != nil {
+		return ""
+	}
+	return cookie.Value
+}
+
+func hashPassword(password string) string {
+	sum := md5.Sum([]byte(password))
+	return hex.EncodeToString(sum[:])
+}
+
+func writeJSON(w http.ResponseWriter, status int, resp Response) {
+	body := map[string]any{}
+	switch {
+	case resp.Error != "":
+		body["Error"] = resp.Error
+	case resp.Name != "":
+		body["name"] = resp.Name
+		body["fields"] = resp.Fields
+		body["records"] = resp.Records
+	default:
+		body["AffectedRows"] = resp.AffectedRows
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,178 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vdriver_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/louis77/valentina-go/vdriver"
+)
+
+func TestBearerAuth(t *testing.T) {
+	srv := startFake(t)
+
+	var rt recordingTransport
+	connector := vdriver.NewConnector(vdriver.VendorValentina, vdriver.Config{
+		User:     "sa",
+		Password: "sa",
+		Host:     srv.Host(),
+		Port:     srv.Port(),
+	}, vdriver.WithTransport(&rt), vdriver.WithAuthenticator(vdriver.BearerAuth("s3cr3t", nil)))
+
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		t.Fatalf("failed to ping: %v", err)
+	}
+
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	for _, req := range rt.requests {
+		if auth := req.Header.Get("Authorization"); auth != "Bearer s3cr3t" {
+			t.Errorf("%s: Authorization is %q", req.URL.Path, auth)
+		}
+	}
+}
+
+func TestBasicAuth(t *testing.T) {
+	srv := startFake(t)
+	srv.AddUser("app", "secret")
+
+	var rt recordingTransport
+	login := vdriver.MD5Auth("app", "secret")
+	connector := vdriver.NewConnector(vdriver.VendorValentina, vdriver.Config{
+		Host: srv.Host(),
+		Port: srv.Port(),
+	}, vdriver.WithTransport(&rt), vdriver.WithAuthenticator(vdriver.BasicAuth("proxy", "pass", login)))
+
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		t.Fatalf("failed to ping: %v", err)
+	}
+
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	for _, req := range rt.requests {
+		user, password, ok := req.BasicAuth()
+		if !ok || user != "proxy" || password != "pass" {
+			t.Errorf("%s: basic auth is %q, %q, %v", req.URL.Path, user, password, ok)
+		}
+	}
+}
+
+func TestCredentialsFunc(t *testing.T) {
+	srv := startFake(t)
+	srv.AddUser("app", "secret")
+
+	calls := 0
+	auth := vdriver.CredentialsFunc(func(ctx context.Context) (string, string, error) {
+		calls++
+		return "app", "secret", nil
+	})
+	connector := vdriver.NewConnector(vdriver.VendorValentina, vdriver.Config{
+		Host: srv.Host(),
+		Port: srv.Port(),
+	}, vdriver.WithAuthenticator(auth))
+
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		t.Fatalf("failed to ping: %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("credentials were requested %d times, want 1", calls)
+	}
+
+	errVault := errors.New("vault is sealed")
+	failing := vdriver.CredentialsFunc(func(ctx context.Context) (string, string, error) {
+		return "", "", errVault
+	})
+	db2 := sql.OpenDB(vdriver.NewConnector(vdriver.VendorValentina, vdriver.Config{
+		Host: srv.Host(),
+		Port: srv.Port(),
+	}, vdriver.WithAuthenticator(failing)))
+	defer db2.Close()
+
+	if err := db2.Ping(); !errors.Is(err, errVault) {
+		t.Fatalf("Ping returned %v, want the error of the credentials provider", err)
+	}
+}
+
+func TestRegisteredAuthenticator(t *testing.T) {
+	srv := startFake(t)
+	srv.AddUser("app", "secret")
+
+	t.Setenv("VTEST_USER", "app")
+	t.Setenv("VTEST_PASSWORD", "secret")
+	if err := vdriver.RegisterAuthenticator("vtest-env", vdriver.EnvCredentials("VTEST_USER", "VTEST_PASSWORD")); err != nil {
+		t.Fatalf("failed to register authenticator: %v", err)
+	}
+	defer vdriver.DeregisterAuthenticator("vtest-env")
+
+	dsn := fmt.Sprintf("http://%s:%d/?auth=vtest-env", srv.Host(), srv.Port())
+	db := openDSN(t, "valentina", dsn)
+	if err := db.Ping(); err != nil {
+		t.Fatalf("failed to ping: %v", err)
+	}
+
+	db = openDSN(t, "valentina", fmt.Sprintf("http://%s:%d/?auth=missing", srv.Host(), srv.Port()))
+	if err := db.Ping(); err == nil || !strings.Contains(err.Error(), "unknown authenticator") {
+		t.Fatalf("Ping returned %v, want an unknown authenticator error", err)
+	}
+}
+
+func TestFileCredentials(t *testing.T) {
+	srv := startFake(t)
+	srv.AddUser("app", "secret")
+
+	path := filepath.Join(t.TempDir(), "credentials")
+	if err := os.WriteFile(path, []byte("app\nsecret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	connector := vdriver.NewConnector(vdriver.VendorValentina, vdriver.Config{
+		Host: srv.Host(),
+		Port: srv.Port(),
+	}, vdriver.WithAuthenticator(vdriver.FileCredentials(path)))
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		t.Fatalf("failed to ping: %v", err)
+	}
+
+	user, password, err := vdriver.FileCredentials(filepath.Join(t.TempDir(), "missing")).Credentials(t.Context())
+	if err == nil {
+		t.Fatalf("Credentials returned %q, %q for a missing file", user, password)
+	}
+}
+
+func TestFormatDSNWithoutPassword(t *testing.T) {
+	cfg := vdriver.Config{User: "app", Host: "localhost", Port: 19998, Auth: "env"}
+
+	dsn := cfg.FormatDSN()
+	if want := "http://app@localhost:19998/?auth=env"; dsn != want {
+		t.Errorf("FormatDSN() = %q, want %q", dsn, want)
+	}
+
+	parsed, err := vdriver.ParseDSN(dsn)
+	if err != nil {
+		t.Fatalf("failed to parse %q: %v", dsn, err)
+	}
+	if parsed.User != cfg.User || parsed.Password != "" || parsed.Auth != cfg.Auth {
+		t.Errorf("ParseDSN(%q) = %+v", dsn, parsed)
+	}
+}
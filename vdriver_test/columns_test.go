@@ -0,0 +1,197 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vdriver_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/louis77/valentina-go/vdriver/vtest"
+)
+
+func TestColumnTypes(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("SELECT id, name, price, 1 AS one FROM products",
+		vtest.Table([]string{"id", "name", "price", "one"}, []any{1, "Pencil", 1.25, 1}))
+	srv.Handle("SHOW COLUMNS FROM products",
+		vtest.Table([]string{"fld_name", "fld_type_str", "fld_nullable", "fld_max_length", "fld_precision", "fld_scale"},
+			[]any{"id", "ULONG", false, 0, 0, 0},
+			[]any{"name", "VARCHAR", true, 40, 0, 0},
+			[]any{"price", "MONEY", true, 0, 10, 2},
+		))
+
+	db := openFake(t, srv)
+	db.SetMaxOpenConns(1)
+
+	for range 2 {
+		rows, err := db.Query("SELECT id, name, price, 1 AS one FROM products")
+		if err != nil {
+			t.Fatalf("failed to query: %v", err)
+		}
+		types, err := rows.ColumnTypes()
+		if err != nil {
+			t.Fatalf("failed to get column types: %v", err)
+		}
+		rows.Close()
+
+		if got := types[0].DatabaseTypeName(); got != "ULONG" {
+			t.Errorf("id: type is %q, expected ULONG", got)
+		}
+		if nullable, ok := types[0].Nullable(); !ok || nullable {
+			t.Errorf("id: nullable is %v/%v, expected false", nullable, ok)
+		}
+		if length, ok := types[1].Length(); !ok || length != 40 {
+			t.Errorf("name: length is %v/%v, expected 40", length, ok)
+		}
+		if got := types[1].ScanType(); got != reflect.TypeFor[string]() {
+			t.Errorf("name: scan type is %v, expected string", got)
+		}
+		if precision, scale, ok := types[2].DecimalSize(); !ok || precision != 10 || scale != 2 {
+			t.Errorf("price: decimal size is %v,%v/%v, expected 10,2", precision, scale, ok)
+		}
+		if got := types[3].DatabaseTypeName(); got != "" {
+			t.Errorf("one: type is %q, expected unknown", got)
+		}
+	}
+
+	lookups := 0
+	for _, req := range srv.Requests() {
+		if req.Query == "SHOW COLUMNS FROM products" {
+			lookups++
+		}
+	}
+	if lookups != 1 {
+		t.Fatalf("expected 1 column lookup, got %d", lookups)
+	}
+}
+
+func TestColumnTypesAliasedExpression(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("SHOW COLUMNS FROM docs",
+		vtest.Table([]string{"fld_name", "fld_type_str"}, []any{"id", "ULONG"}, []any{"data", "BLOB"}))
+	srv.Handle("SELECT id, hex(data) AS data FROM docs",
+		vtest.Table([]string{"id", "data"}, []any{1, "4142"}))
+
+	db := openFake(t, srv)
+
+	// The alias is named like a BLOB column, but the expression returns text
+	rows, err := db.Query("SELECT id, hex(data) AS data FROM docs")
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	defer rows.Close()
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		t.Fatalf("failed to get column types: %v", err)
+	}
+	if got := types[0].DatabaseTypeName(); got != "ULONG" {
+		t.Errorf("id: type is %q, expected ULONG", got)
+	}
+	if got := types[1].DatabaseTypeName(); got != "" {
+		t.Errorf("data: type is %q, expected unknown", got)
+	}
+
+	var id int64
+	var data any
+	rows.Next()
+	if err := rows.Scan(&id, &data); err != nil {
+		t.Fatalf("failed to scan: %v", err)
+	}
+	if data != "4142" {
+		t.Errorf("data is %#v, expected the text 4142", data)
+	}
+}
+
+func TestColumnTypesSQLite(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("SELECT * FROM notes WHERE id = ?", vtest.Table([]string{"id", "body"}, []any{1, "hello"}))
+	srv.Handle("PRAGMA table_info(notes)",
+		vtest.Table([]string{"cid", "name", "type", "notnull", "dflt_value", "pk"},
+			[]any{0, "id", "INTEGER", 1, nil, 1},
+			[]any{1, "body", "varchar(200)", 0, nil, 0},
+		))
+
+	db := openDSN(t, "vsqlite", srv.DSN(""))
+	rows, err := db.Query("SELECT * FROM notes WHERE id = ?", 1)
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	defer rows.Close()
+
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		t.Fatalf("failed to get column types: %v", err)
+	}
+	if got := types[0].DatabaseTypeName(); got != "INTEGER" {
+		t.Errorf("id: type is %q, expected INTEGER", got)
+	}
+	if nullable, ok := types[1].Nullable(); !ok || !nullable {
+		t.Errorf("body: nullable is %v/%v, expected true", nullable, ok)
+	}
+	if length, ok := types[1].Length(); !ok || length != 200 {
+		t.Errorf("body: length is %v/%v, expected 200", length, ok)
+	}
+}
+
+func TestColumnTypesInvalidatedByDDL(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("SELECT id FROM products", vtest.Table([]string{"id"}, []any{1}))
+	srv.Handle("ALTER TABLE products ALTER COLUMN id LLONG", vtest.NoResult())
+	srv.Handle("SHOW COLUMNS FROM products",
+		vtest.Table([]string{"fld_name", "fld_type_str"}, []any{"id", "ULONG"}),
+		vtest.Table([]string{"fld_name", "fld_type_str"}, []any{"id", "LLONG"}),
+	)
+
+	db := openFake(t, srv)
+	db.SetMaxOpenConns(1)
+
+	typeName := func() string {
+		t.Helper()
+		rows, err := db.Query("SELECT id FROM products")
+		if err != nil {
+			t.Fatalf("failed to query: %v", err)
+		}
+		defer rows.Close()
+		types, err := rows.ColumnTypes()
+		if err != nil {
+			t.Fatalf("failed to get column types: %v", err)
+		}
+		return types[0].DatabaseTypeName()
+	}
+
+	if got := typeName(); got != "ULONG" {
+		t.Fatalf("type is %q, expected ULONG", got)
+	}
+	if _, err := db.Exec("ALTER TABLE products ALTER COLUMN id LLONG"); err != nil {
+		t.Fatalf("failed to alter table: %v", err)
+	}
+	if got := typeName(); got != "LLONG" {
+		t.Errorf("type is %q after ALTER TABLE, expected LLONG", got)
+	}
+}
+
+func TestColumnTypesBeforeQuery(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("SELECT id FROM products", vtest.Table([]string{"id"}, []any{1}))
+	srv.Handle("SHOW COLUMNS FROM products", vtest.Table([]string{"fld_name", "fld_type_str"}, []any{"id", "ULONG"}))
+
+	db := openFake(t, srv)
+	var id any
+	if err := db.QueryRow("SELECT id FROM products").Scan(&id); err != nil {
+		t.Fatalf("failed to scan: %v", err)
+	}
+
+	// The session can't be used while the records are streamed
+	var order []string
+	for _, req := range srv.Requests() {
+		if req.Query == "SELECT id FROM products" || req.Query == "SHOW COLUMNS FROM products" {
+			order = append(order, req.Query)
+		}
+	}
+	want := []string{"SHOW COLUMNS FROM products", "SELECT id FROM products"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("requests are %q, expected %q", order, want)
+	}
+}
@@ -6,6 +6,10 @@ package vdriver_test
 
 import (
 	"database/sql"
+	"database/sql/driver"
+	"net/http"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/louis77/valentina-go/vdriver"
@@ -54,3 +58,122 @@ func TestConnectorShouldFail(t *testing.T) {
 		t.Fatalf("should have failed")
 	}
 }
+
+// recordingTransport records the requests sent through it.
+type recordingTransport struct {
+	mu       sync.Mutex
+	requests []*http.Request
+}
+
+func (rt *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.mu.Lock()
+	rt.requests = append(rt.requests, req)
+	rt.mu.Unlock()
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestConnectorOptions(t *testing.T) {
+	srv := startFake(t)
+
+	var rt recordingTransport
+	connector := vdriver.NewConnector(vdriver.VendorValentina, vdriver.Config{
+		User:     "sa",
+		Password: "sa",
+		Host:     srv.Host(),
+		Port:     srv.Port(),
+	}, vdriver.WithTransport(&rt), vdriver.WithUserAgent("billing/1.0"), vdriver.WithHeader("X-Tenant", "acme"))
+
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		t.Fatalf("failed to ping: %v", err)
+	}
+
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	if len(rt.requests) == 0 {
+		t.Fatal("no requests went through the transport")
+	}
+	for _, req := range rt.requests {
+		if ua := req.Header.Get("User-Agent"); ua != "billing/1.0" {
+			t.Errorf("%s: User-Agent is %q", req.URL.Path, ua)
+		}
+		if tenant := req.Header.Get("X-Tenant"); tenant != "acme" {
+			t.Errorf("%s: X-Tenant is %q", req.URL.Path, tenant)
+		}
+	}
+}
+
+func TestConnectorHTTPClient(t *testing.T) {
+	srv := startFake(t)
+
+	var rt recordingTransport
+	connector := vdriver.NewConnector(vdriver.VendorValentina, vdriver.Config{
+		User:     "sa",
+		Password: "sa",
+		Host:     srv.Host(),
+		Port:     srv.Port(),
+	}, vdriver.WithHTTPClient(&http.Client{Transport: &rt}))
+
+	db := sql.OpenDB(connector)
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		t.Fatalf("failed to ping: %v", err)
+	}
+	if len(rt.requests) == 0 {
+		t.Fatal("no requests went through the client")
+	}
+}
+
+func TestConnectorUnknownVendor(t *testing.T) {
+	srv := startFake(t)
+
+	for _, vendor := range []vdriver.Vendor{"vsqlite", "Postgres"} {
+		connector := vdriver.NewConnector(vendor, vdriver.Config{
+			User:     "sa",
+			Password: "sa",
+			Host:     srv.Host(),
+			Port:     srv.Port(),
+		})
+		db := sql.OpenDB(connector)
+		if err := db.Ping(); err == nil || !strings.Contains(err.Error(), "unknown vendor") {
+			t.Errorf("%s: expected an unknown vendor error, got %v", vendor, err)
+		}
+		db.Close()
+	}
+	if n := len(srv.Requests()); n != 0 {
+		t.Errorf("%d requests were sent for unknown vendors", n)
+	}
+
+	if vendor, err := vdriver.ParseVendor("duckdb"); err != nil || vendor != vdriver.VendorDuckDB {
+		t.Errorf("ParseVendor(duckdb) = %q, %v", vendor, err)
+	}
+}
+
+func TestOpenConnector(t *testing.T) {
+	srv := startFake(t)
+
+	db := openFake(t, srv)
+	dc, ok := db.Driver().(driver.DriverContext)
+	if !ok {
+		t.Fatal("driver does not implement driver.DriverContext")
+	}
+
+	if _, err := dc.OpenConnector(srv.DSN("") + "?vendr=SQLite"); err == nil {
+		t.Fatal("OpenConnector should reject an invalid DSN")
+	}
+
+	connector, err := dc.OpenConnector(srv.DSN(""))
+	if err != nil {
+		t.Fatalf("failed to open connector: %v", err)
+	}
+	conn, err := connector.Connect(t.Context())
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	if err := conn.Close(); err != nil {
+		t.Fatalf("failed to close: %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vdriver_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/louis77/valentina-go/vdriver/vtest"
+)
+
+// handleSlow makes srv block on query until the test ends.
+func handleSlow(t *testing.T, srv *vtest.Server, query string) {
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	srv.HandleFunc(func(req vtest.Request) (vtest.Response, bool) {
+		if req.Query != query {
+			return vtest.Response{}, false
+		}
+		<-release
+		return vtest.Affected(1), true
+	})
+}
+
+func TestQueryTimeout(t *testing.T) {
+	srv := startFake(t)
+	handleSlow(t, srv, "UPDATE t SET x = 1")
+
+	db := openDSN(t, "valentina", srv.DSN("")+"?queryTimeout=50ms")
+	db.SetMaxOpenConns(1)
+
+	_, err := db.Exec("UPDATE t SET x = 1")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+
+	// The connection is stuck in the canceled query, so a new session must be used
+	var version string
+	if err := db.QueryRow("SELECT version()").Scan(&version); err != nil {
+		t.Fatalf("failed to query after timeout: %v", err)
+	}
+	if n := srv.Logins(); n != 2 {
+		t.Fatalf("expected 2 logins, got %d", n)
+	}
+	if n := len(srv.Sessions()); n != 1 {
+		t.Fatalf("expected the canceled session to be removed, %d sessions open", n)
+	}
+}
+
+func TestStmtContext(t *testing.T) {
+	srv := startFake(t)
+	handleSlow(t, srv, "SELECT * FROM t WHERE id = :1")
+
+	db := openFake(t, srv)
+	stmt, err := db.Prepare("SELECT * FROM t WHERE id = :1")
+	if err != nil {
+		t.Fatalf("failed to prepare: %v", err)
+	}
+	defer stmt.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = stmt.QueryContext(ctx, 1)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
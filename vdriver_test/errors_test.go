@@ -0,0 +1,188 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vdriver_test
+
+import (
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/louis77/valentina-go/vdriver"
+	"github.com/louis77/valentina-go/vdriver/vtest"
+)
+
+func TestErrorClassification(t *testing.T) {
+	srv := startFake(t)
+
+	tests := []struct {
+		query string
+		msg   string
+		is    func(error) bool
+	}{
+		{"SELEC 1", "Syntax error near 'SELEC'", vdriver.IsSyntaxError},
+		{"SELECT * FROM missing", "Table \"missing\" not found", vdriver.IsNotFound},
+		{"INSERT INTO t VALUES (1)", "UNIQUE constraint failed: t.id", vdriver.IsUniqueViolation},
+	}
+
+	db := openFake(t, srv)
+	for _, tt := range tests {
+		srv.Handle(tt.query, vtest.Failure(400, tt.msg))
+
+		_, err := db.Exec(tt.query)
+		if !tt.is(err) {
+			t.Errorf("%s: unexpected classification of %v", tt.query, err)
+		}
+
+		var verr *vdriver.Error
+		if !errors.As(err, &verr) {
+			t.Fatalf("%s: expected *vdriver.Error, got %T", tt.query, err)
+		}
+		if verr.Message != tt.msg || verr.Query != tt.query || verr.StatusCode != 400 || verr.Vendor != vdriver.VendorValentina {
+			t.Errorf("%s: unexpected error fields: %+v", tt.query, verr)
+		}
+		if vdriver.IsSessionExpired(err) || vdriver.IsAuthFailed(err) {
+			t.Errorf("%s: error misclassified: %v", tt.query, err)
+		}
+	}
+}
+
+func TestErrorAuthFailed(t *testing.T) {
+	srv := startFake(t)
+	srv.AddUser("sa", "secret")
+
+	db := openFake(t, srv)
+	err := db.Ping()
+	if !vdriver.IsAuthFailed(err) {
+		t.Fatalf("expected authentication error, got %v", err)
+	}
+}
+
+func TestErrorSessionExpired(t *testing.T) {
+	srv := startFake(t)
+
+	db := openFake(t, srv)
+	conn, err := db.Conn(t.Context())
+	if err != nil {
+		t.Fatalf("failed to get connection: %v", err)
+	}
+	defer conn.Close()
+
+	srv.ExpireSessions()
+
+	_, err = conn.ExecContext(t.Context(), "DELETE FROM t")
+	if !vdriver.IsSessionExpired(err) {
+		t.Fatalf("expected session expired error, got %v", err)
+	}
+}
+
+func TestErrorClassificationIgnoresIdentifiers(t *testing.T) {
+	srv := startFake(t)
+
+	tests := []struct {
+		query string
+		msg   string
+		is    func(error) bool
+		isNot []func(error) bool
+	}{
+		{"SELECT password FROM t", "no such column: password", vdriver.IsNotFound,
+			[]func(error) bool{vdriver.IsAuthFailed}},
+		{"SELECT unique_id FROM t", "Field 'unique_id' not found", vdriver.IsNotFound,
+			[]func(error) bool{vdriver.IsUniqueViolation}},
+		{"SELECT \"syntax error\" FROM t", "Binder Error: Referenced column \"syntax error\" not found in FROM clause!", vdriver.IsNotFound,
+			[]func(error) bool{vdriver.IsSyntaxError}},
+		{"SELECT * FROM not_found", "no such table: not_found_table", vdriver.IsNotFound,
+			[]func(error) bool{vdriver.IsSyntaxError, vdriver.IsUniqueViolation}},
+		{"UPDATE access SET denied = 1", "Constraint Error: Duplicate key \"id: 1\" violates primary key constraint", vdriver.IsUniqueViolation,
+			[]func(error) bool{vdriver.IsAuthFailed, vdriver.IsNotFound}},
+		{"SELECT 'access denied'", "near \"access denied\": syntax error", vdriver.IsSyntaxError,
+			[]func(error) bool{vdriver.IsAuthFailed}},
+		{"SELECT * FROM users", "Access denied for table users", nil,
+			[]func(error) bool{vdriver.IsAuthFailed}},
+	}
+
+	db := openFake(t, srv)
+	for _, tt := range tests {
+		srv.Handle(tt.query, vtest.Failure(400, tt.msg))
+
+		_, err := db.Exec(tt.query)
+		if tt.is != nil && !tt.is(err) {
+			t.Errorf("%q: not classified: %v", tt.msg, err)
+		}
+		for i, is := range tt.isNot {
+			if is(err) {
+				t.Errorf("%q: misclassified by check %d", tt.msg, i)
+			}
+		}
+	}
+}
+
+// proxyTransport answers sql_fast requests with a plain text error page, like a reverse proxy.
+type proxyTransport struct {
+	status int
+	page   string
+}
+
+func (pt *proxyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if pt.status == 0 || !strings.HasSuffix(req.URL.Path, "/sql_fast") {
+		return http.DefaultTransport.RoundTrip(req)
+	}
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	return &http.Response{
+		StatusCode: pt.status,
+		Header:     http.Header{"Content-Type": {"text/plain"}},
+		Body:       io.NopCloser(strings.NewReader(pt.page)),
+		Request:    req,
+	}, nil
+}
+
+func TestErrorProxyPages(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("SELECT 1", vtest.Table([]string{"1"}, []any{1}))
+
+	pt := &proxyTransport{}
+	db := sql.OpenDB(vdriver.NewConnector(vdriver.VendorValentina, vdriver.Config{
+		User:     "sa",
+		Password: "sa",
+		Host:     srv.Host(),
+		Port:     srv.Port(),
+	}, vdriver.WithTransport(pt)))
+	defer db.Close()
+	if err := db.Ping(); err != nil {
+		t.Fatalf("failed to ping: %v", err)
+	}
+
+	tests := []struct {
+		status int
+		page   string
+		is     func(error) bool
+	}{
+		{http.StatusUnauthorized, "Unauthorized\n", vdriver.IsAuthFailed},
+		{http.StatusBadGateway, "<html>502 Bad Gateway</html>", func(err error) bool { return !vdriver.IsAuthFailed(err) }},
+	}
+
+	for _, tt := range tests {
+		pt.status, pt.page = tt.status, tt.page
+
+		_, execErr := db.Exec("DELETE FROM t")
+		_, queryErr := db.Query("SELECT 1")
+		for _, err := range []error{execErr, queryErr} {
+			var verr *vdriver.Error
+			if !errors.As(err, &verr) {
+				t.Fatalf("%d: expected *vdriver.Error, got %v", tt.status, err)
+			}
+			if verr.StatusCode != tt.status || verr.Message != strings.TrimSpace(tt.page) {
+				t.Errorf("%d: unexpected error fields: %+v", tt.status, verr)
+			}
+			if !tt.is(err) {
+				t.Errorf("%d: unexpected classification of %v", tt.status, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vdriver_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/louis77/valentina-go/vdriver"
+	"github.com/louis77/valentina-go/vdriver/vtest"
+)
+
+func TestLastInsertID(t *testing.T) {
+	tests := []struct {
+		vendor string
+		query  string // Query sent for the INSERT
+		lookup string // Query that returns the ID, if any
+	}{
+		{"Valentina", "INSERT INTO t (name) VALUES (:1) -- comment", "SELECT Last_RecID()"},
+		{"SQLite", "INSERT INTO t (name) VALUES (:1) -- comment", "SELECT last_insert_rowid()"},
+		{"DuckDB", "INSERT INTO t (name) VALUES (:1) RETURNING rowid", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.vendor, func(t *testing.T) {
+			srv := startFake(t)
+			if tt.lookup != "" {
+				srv.Handle(tt.query, vtest.Affected(1))
+				srv.Handle(tt.lookup, vtest.Table([]string{"id"}, []any{42}))
+			} else {
+				srv.Handle(tt.query, vtest.Table([]string{"rowid"}, []any{41}, []any{42}))
+			}
+
+			db := openDSN(t, "valentina", srv.DSN("")+"?lastInsertId=auto&vendor="+tt.vendor)
+			result, err := db.Exec("INSERT INTO t (name) VALUES (:1) -- comment", "Alice")
+			if err != nil {
+				t.Fatalf("failed to exec: %v", err)
+			}
+
+			id, err := result.LastInsertId()
+			if err != nil || id != 42 {
+				t.Errorf("last insert ID is %v, %v, expected 42", id, err)
+			}
+
+			sessions := make(map[string]bool)
+			for _, req := range srv.Requests() {
+				sessions[req.SessionID] = true
+			}
+			if len(sessions) != 1 {
+				t.Errorf("expected the lookup on the same session, got %d sessions", len(sessions))
+			}
+		})
+	}
+}
+
+func TestLastInsertIDDisabled(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("INSERT INTO t (name) VALUES (:1)", vtest.Affected(1))
+	srv.Handle("UPDATE t SET name = :1", vtest.Affected(1))
+
+	db := openFake(t, srv)
+	result, err := db.Exec("INSERT INTO t (name) VALUES (:1)", "Alice")
+	if err != nil {
+		t.Fatalf("failed to exec: %v", err)
+	}
+	if _, err := result.LastInsertId(); !errors.Is(err, vdriver.ErrNotSupported) {
+		t.Errorf("expected ErrNotSupported, got %v", err)
+	}
+
+	// Only INSERTs are followed by a lookup
+	db = openDSN(t, "valentina", srv.DSN("")+"?lastInsertId=auto")
+	if _, err := db.Exec("UPDATE t SET name = :1", "Bob"); err != nil {
+		t.Fatalf("failed to exec: %v", err)
+	}
+	for _, q := range queries(srv) {
+		if q == "SELECT Last_RecID()" {
+			t.Errorf("unexpected lookup of the last insert ID")
+		}
+	}
+}
+
+func TestLastInsertIDLookupFailed(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("INSERT INTO t (name) VALUES (:1)", vtest.Affected(1))
+	srv.Handle("SELECT Last_RecID()", vtest.Failure(500, "Function Last_RecID is unknown"))
+
+	db := openDSN(t, "valentina", srv.DSN("")+"?lastInsertId=auto")
+	result, err := db.Exec("INSERT INTO t (name) VALUES (:1)", "Alice")
+	if err != nil {
+		t.Fatalf("a failed lookup must not fail the INSERT: %v", err)
+	}
+	if _, err := result.LastInsertId(); err == nil {
+		t.Errorf("expected an error from LastInsertId")
+	}
+	if n, _ := result.RowsAffected(); n != 1 {
+		t.Errorf("affected rows are %d, expected 1", n)
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vdriver_test
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/louis77/valentina-go/vdriver/vtest"
+)
+
+func TestNumInput(t *testing.T) {
+	srv := startFake(t)
+	srv.HandleFunc(func(req vtest.Request) (vtest.Response, bool) {
+		return vtest.Affected(1), true
+	})
+	db := openFake(t, srv)
+
+	tests := []struct {
+		query string
+		want  int // -1 if the driver can't tell
+	}{
+		{"DELETE FROM t", 0},
+		{"DELETE FROM t WHERE a = ? AND b = ?", 2},
+		{"DELETE FROM t WHERE a = :1 AND b = :2 OR c = :1", 2},
+		{"DELETE FROM t WHERE a = :3", 3},
+		{"DELETE FROM t WHERE a = '?' AND \"b?\" = [c:1] AND d = ? -- ?\n/* :2 */", 1},
+		{"SELECT $$a ? :1$$, $fn$ $$ ? $fn$, ?", 1},
+		{"DELETE FROM t WHERE a = ?::INT AND b = @@x", 1},
+		{"DELETE FROM t WHERE a = ? AND b = :2", -1},
+		{"DELETE FROM t WHERE a = :name", -1},
+		{"DELETE FROM t WHERE a = $1", -1},
+	}
+
+	for _, tt := range tests {
+		stmt, err := db.Prepare(tt.query)
+		if err != nil {
+			t.Fatalf("%s: failed to prepare: %v", tt.query, err)
+		}
+
+		if tt.want >= 0 {
+			args := make([]any, tt.want+1)
+			for i := range args {
+				args[i] = i
+			}
+			_, err = stmt.Exec(args...)
+			if err == nil || !strings.Contains(err.Error(), "expected") {
+				t.Errorf("%s: expected an argument count error, got %v", tt.query, err)
+			}
+			args = args[:tt.want]
+			if _, err := stmt.Exec(args...); err != nil {
+				t.Errorf("%s: failed to exec with %d arguments: %v", tt.query, tt.want, err)
+			}
+		}
+		stmt.Close()
+	}
+}
+
+func TestNumInputDuckDBBrackets(t *testing.T) {
+	srv := startFake(t)
+	srv.HandleFunc(func(req vtest.Request) (vtest.Response, bool) {
+		return vtest.Affected(1), true
+	})
+	db := openDSN(t, "vduckdb", srv.DSN(""))
+
+	// On DuckDB brackets are lists and subscripts, not quoted identifiers, and their colons
+	// are slices and struct keys, not placeholders
+	tests := []struct {
+		query string
+		args  []any
+	}{
+		{"SELECT [?, ?]", []any{1, 2}},
+		{"SELECT list[?] FROM t", []any{1}},
+		{"SELECT l[1:2] FROM t", nil},
+		{"SELECT l[1:2][:3] FROM t WHERE id = :1", []any{1}},
+		{"SELECT {'a':1, 'b': {'c':2}}, ?", []any{1}},
+		{"SELECT l[?:?] FROM t", []any{1, 2}},
+		{"SELECT [?]; SELECT list[?] FROM t", []any{1, 2}},
+	}
+
+	for _, tt := range tests {
+		stmt, err := db.Prepare(tt.query)
+		if err != nil {
+			t.Fatalf("%s: failed to prepare: %v", tt.query, err)
+		}
+		if _, err := stmt.Exec(tt.args...); err != nil {
+			t.Errorf("%s: failed to exec: %v", tt.query, err)
+		}
+		stmt.Close()
+	}
+
+	reqs := srv.Requests()
+	if last := reqs[len(reqs)-1]; last.Query != "SELECT list[?] FROM t" || len(last.Params) != 1 || jsonString(last.Params[0]) != "2" {
+		t.Errorf("unexpected last statement: %q %v", last.Query, last.Params)
+	}
+
+	if _, err := db.Exec("SELECT l[1:2] FROM t WHERE id = :id", sql.Named("id", 7)); err != nil {
+		t.Errorf("failed to exec slice with named parameter: %v", err)
+	}
+	reqs = srv.Requests()
+	if last := reqs[len(reqs)-1]; last.Query != "SELECT l[1:2] FROM t WHERE id = :1" || len(last.Params) != 1 {
+		t.Errorf("unexpected named statement: %q %v", last.Query, last.Params)
+	}
+}
@@ -0,0 +1,223 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vdriver_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/louis77/valentina-go/vdriver"
+	"github.com/louis77/valentina-go/vdriver/vtest"
+)
+
+// logBuffer collects the JSON events of a logger.
+type logBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *logBuffer) logger() *slog.Logger {
+	return slog.New(slog.NewJSONHandler(b, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+// events returns the logged events with the given message.
+func (b *logBuffer) events(t *testing.T, msg string) []map[string]any {
+	t.Helper()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var events []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(b.buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var event map[string]any
+		if err := json.Unmarshal([]byte(line), &event); err != nil {
+			t.Fatalf("invalid log line %q: %v", line, err)
+		}
+		if event["msg"] == msg {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// sqlEvent returns the sql_fast event of the query.
+func (b *logBuffer) sqlEvent(t *testing.T, query string) map[string]any {
+	t.Helper()
+	for _, event := range b.events(t, "valentina sql_fast") {
+		if event["query"] == query {
+			return event
+		}
+	}
+	t.Fatalf("no sql_fast event for %q", query)
+	return nil
+}
+
+func TestLogger(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("SELECT name FROM products WHERE price > :1",
+		vtest.Table([]string{"name"}, []any{"Pencil"}, []any{"Pen"}))
+	srv.Handle("DELETE FROM products WHERE name = :1", vtest.Affected(3))
+
+	var logs logBuffer
+	db := sql.OpenDB(vdriver.NewConnector(vdriver.VendorValentina, vdriver.Config{
+		User:     "sa",
+		Password: "sa",
+		Host:     srv.Host(),
+		Port:     srv.Port(),
+	}, vdriver.WithLogger(logs.logger())))
+	defer db.Close()
+
+	rows, err := db.Query("SELECT name FROM products WHERE price > :1", 1.5)
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	for rows.Next() {
+	}
+	rows.Close()
+
+	if _, err := db.Exec("DELETE FROM products WHERE name = :1", "top-secret"); err != nil {
+		t.Fatalf("failed to exec: %v", err)
+	}
+
+	if n := len(logs.events(t, "valentina session created")); n != 1 {
+		t.Errorf("%d session created events, expected 1", n)
+	}
+
+	event := logs.sqlEvent(t, "SELECT name FROM products WHERE price > :1")
+	if event["args"] != 1.0 || event["rows"] != 2.0 || event["status"] != 200.0 || event["level"] != "DEBUG" {
+		t.Errorf("unexpected query event: %v", event)
+	}
+	if _, ok := event["duration"]; !ok {
+		t.Errorf("query event has no duration: %v", event)
+	}
+
+	event = logs.sqlEvent(t, "DELETE FROM products WHERE name = :1")
+	if event["affected_rows"] != 3.0 {
+		t.Errorf("unexpected exec event: %v", event)
+	}
+	if _, ok := event["rows"]; ok {
+		t.Errorf("exec event has rows: %v", event)
+	}
+	if strings.Contains(logs.buf.String(), "top-secret") {
+		t.Error("parameter values are logged without logParams")
+	}
+}
+
+func TestLoggerErrors(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("SELEC 1", vtest.Failure(400, "syntax error"))
+
+	var logs logBuffer
+	if err := vdriver.RegisterLogger("vtest-errors", logs.logger()); err != nil {
+		t.Fatalf("failed to register logger: %v", err)
+	}
+	defer vdriver.DeregisterLogger("vtest-errors")
+
+	db := openDSN(t, "valentina", srv.DSN("")+"?logger=vtest-errors&logParams=true")
+	if _, err := db.Exec("SELEC 1"); err == nil {
+		t.Fatal("expected the statement to fail")
+	}
+	if _, err := db.Exec("SET PROPERTY Foo OF DATABASE TO :1", "visible"); err != nil {
+		t.Fatalf("failed to exec: %v", err)
+	}
+
+	event := logs.sqlEvent(t, "SELEC 1")
+	if event["level"] != "ERROR" || !strings.Contains(event["error"].(string), "syntax error") || event["status"] != 400.0 {
+		t.Errorf("unexpected error event: %v", event)
+	}
+	event = logs.sqlEvent(t, "SET PROPERTY Foo OF DATABASE TO :1")
+	if params, _ := event["params"].([]any); len(params) != 1 || params[0] != "visible" {
+		t.Errorf("parameters are not logged with logParams: %v", event)
+	}
+
+	db = openDSN(t, "valentina", srv.DSN("")+"?logger=missing")
+	if err := db.Ping(); err == nil || !strings.Contains(err.Error(), "unknown logger") {
+		t.Fatalf("Ping returned %v, want an unknown logger error", err)
+	}
+}
+
+func TestLoggerSessionEvents(t *testing.T) {
+	srv := startFake(t)
+
+	var logs logBuffer
+	db := sql.OpenDB(vdriver.NewConnector(vdriver.VendorValentina, vdriver.Config{
+		User:     "sa",
+		Password: "sa",
+		Host:     srv.Host(),
+		Port:     srv.Port(),
+		Relogin:  true,
+	}, vdriver.WithLogger(logs.logger())))
+	defer db.Close()
+
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("failed to get connection: %v", err)
+	}
+	if err := conn.PingContext(ctx); err != nil {
+		t.Fatalf("failed to ping: %v", err)
+	}
+
+	srv.ExpireSessions()
+	if err := conn.PingContext(ctx); err != nil {
+		t.Fatalf("failed to ping after session expired: %v", err)
+	}
+	conn.Close()
+	if err := db.Close(); err != nil {
+		t.Fatalf("failed to close database: %v", err)
+	}
+
+	if n := len(logs.events(t, "valentina session created")); n != 2 {
+		t.Errorf("%d session created events, expected 2", n)
+	}
+	if n := len(logs.events(t, "valentina session expired")); n != 1 {
+		t.Errorf("%d session expired events, expected 1", n)
+	}
+	if n := len(logs.events(t, "valentina session refreshed")); n != 1 {
+		t.Errorf("%d session refreshed events, expected 1", n)
+	}
+	// The expired session was removed by the server, only the refreshed one is closed
+	if n := len(logs.events(t, "valentina session closed")); n != 1 {
+		t.Errorf("%d session closed events, expected 1", n)
+	}
+}
+
+func TestLoggerConnectFailure(t *testing.T) {
+	srv := startFake(t)
+
+	var logs logBuffer
+	db := sql.OpenDB(vdriver.NewConnector(vdriver.VendorValentina, vdriver.Config{
+		User:     "sa",
+		Password: "wrong-password",
+		Host:     srv.Host(),
+		Port:     srv.Port(),
+	}, vdriver.WithLogger(logs.logger())))
+	defer db.Close()
+
+	if err := db.Ping(); err == nil {
+		t.Fatal("expected the login to fail")
+	}
+	events := logs.events(t, "valentina connect failed")
+	if len(events) == 0 {
+		t.Fatal("no connect failed event")
+	}
+	if dsn, _ := events[0]["dsn"].(string); !strings.Contains(dsn, "xxxxx") || strings.Contains(dsn, "wrong-password") {
+		t.Errorf("DSN is not redacted: %q", dsn)
+	}
+}
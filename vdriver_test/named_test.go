@@ -0,0 +1,74 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vdriver_test
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/louis77/valentina-go/vdriver/vtest"
+)
+
+func TestNamedParameters(t *testing.T) {
+	srv := startFake(t)
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{
+			"UPDATE t SET name = :name WHERE id = @id OR parent = :id",
+			"UPDATE t SET name = :2 WHERE id = :1 OR parent = :1",
+		},
+		{
+			"UPDATE t SET name = ':id' || :name, \"a:id\" = [b@id] -- :id\nWHERE /* @id */ id = :id::INT",
+			"UPDATE t SET name = ':id' || :2, \"a:id\" = [b@id] -- :id\nWHERE /* @id */ id = :1::INT",
+		},
+	}
+
+	db := openFake(t, srv)
+	for _, tt := range tests {
+		srv.Handle(tt.want, vtest.Affected(1))
+
+		_, err := db.Exec(tt.query, sql.Named("id", 5), sql.Named("name", "Alice"))
+		if err != nil {
+			t.Fatalf("%s: failed to exec: %v", tt.query, err)
+		}
+
+		reqs := srv.Requests()
+		last := reqs[len(reqs)-1]
+		if last.Query != tt.want {
+			t.Errorf("query is %q, expected %q", last.Query, tt.want)
+		}
+		if len(last.Params) != 2 || last.Params[0].(interface{ String() string }).String() != "5" || last.Params[1] != "Alice" {
+			t.Errorf("unexpected params: %v", last.Params)
+		}
+	}
+}
+
+func TestNamedParametersInvalid(t *testing.T) {
+	srv := startFake(t)
+
+	tests := []struct {
+		query string
+		args  []any
+		err   string
+	}{
+		{"SELECT * FROM t WHERE id = :id AND x = :x", []any{sql.Named("id", 1)}, "placeholder :x has no named argument"},
+		{"SELECT * FROM t WHERE id = :id", []any{sql.Named("id", 1), sql.Named("x", 2)}, `named argument "x" has no placeholder`},
+		{"SELECT * FROM t WHERE id = :id AND x = ?", []any{sql.Named("id", 1)}, "cannot mix"},
+		{"SELECT * FROM t WHERE id = :id AND x = :1", []any{sql.Named("id", 1)}, "cannot mix"},
+		{"SELECT * FROM t WHERE id = :id AND x = :2", []any{sql.Named("id", 1), 2}, "cannot mix"},
+	}
+
+	db := openFake(t, srv)
+	for _, tt := range tests {
+		_, err := db.Query(tt.query, tt.args...)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: expected error %q, got %v", tt.query, tt.err, err)
+		}
+	}
+}
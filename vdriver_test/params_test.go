@@ -0,0 +1,224 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vdriver_test
+
+import (
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/louis77/valentina-go/vdriver"
+	"github.com/louis77/valentina-go/vdriver/vtest"
+	"github.com/louis77/valentina-go/vsql"
+)
+
+type status string
+
+func TestParamConversion(t *testing.T) {
+	srv := startFake(t)
+	srv.HandleFunc(func(req vtest.Request) (vtest.Response, bool) {
+		return vtest.Affected(1), strings.HasPrefix(req.Query, "INSERT")
+	})
+
+	ts := time.Date(2025, 3, 14, 15, 9, 26, 535_000_000, time.UTC)
+	id := int32(7)
+	var nilID *int32
+
+	tests := []struct {
+		name string
+		dsn  string
+		arg  any
+		want any
+	}{
+		{"time", "", ts, "2025-03-14 15:09:26.535"},
+		{"time kDMY", "?dateFormat=kDMY", ts, "14-03-2025 15:09:26.535"},
+		{"time kMDY", "?dateFormat=kMDY", ts, "03-14-2025 15:09:26.535"},
+		{"time SQLite", "?dateFormat=kDMY&vendor=SQLite", ts, "2025-03-14 15:09:26.535"},
+		{"vsql.Time", "", vsql.Time{Time: ts}, "2025-03-14 15:09:26.535"},
+		{"vsql.Time kDMY", "?dateFormat=kDMY", vsql.Time{Time: ts}, "14-03-2025 15:09:26.535"},
+		{"vsql.Date kMDY", "?dateFormat=kMDY", vsql.Date{Time: ts}, "03-14-2025"},
+		{"vsql.Date loc", "?parseTime=true&loc=America%2FNew_York", vsql.Date{Time: ts}, "2025-03-14"},
+		{"blob", "", []byte{0xca, 0xfe, 0x01}, "cafe01"},
+		{"nil blob", "", []byte(nil), nil},
+		{"byte array", "", [3]byte{0xca, 0xfe, 0x01}, "cafe01"},
+		{"json.RawMessage", "", json.RawMessage(`{"a":1}`), `{"a":1}`},
+		{"json.RawMessage SQLite", "?vendor=SQLite", json.RawMessage(`[1]`), "[1]"},
+		{"blob reader", "", strings.NewReader("Hi\x00"), "486900"},
+		{"custom string", "", status("active"), "active"},
+		{"pointer", "", &id, "7"},
+		{"nil pointer", "", nilID, nil},
+		{"int array", "", []int{1, 2, 3}, "{1,2,3}"},
+		{"string array", "", []string{"a", `b"c`, `d\e`}, `{"a","b\"c","d\\e"}`},
+		{"mixed array", "", []any{1.5, nil, true, ts}, `{1.5,NULL,true,"2025-03-14 15:09:26.535"}`},
+		{"vsql.Array", "", vsql.NewArray[int64](1, 2), "{1,2}"},
+		{"vsql.Array of times", "", vsql.NewArray(vsql.Time{Time: ts}), `{"2025-03-14 15:09:26.535"}`},
+		{"DuckDB list", "?vendor=DuckDB", []int64{4, 5}, "[4,5]"},
+		{"uint64", "", uint64(1<<63 + 1), "9223372036854775809"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := openDSN(t, "valentina", srv.DSN("")+tt.dsn)
+
+			if _, err := db.Exec("INSERT INTO t VALUES (:1)", tt.arg); err != nil {
+				t.Fatalf("failed to exec: %v", err)
+			}
+
+			reqs := srv.Requests()
+			params := reqs[len(reqs)-1].Params
+			if len(params) != 1 {
+				t.Fatalf("expected 1 param, got %v", params)
+			}
+			got := params[0]
+			if got != nil {
+				got = fmt.Sprint(got)
+			}
+			if got != tt.want {
+				t.Errorf("param is %#v, expected %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParamConversionUnsupported(t *testing.T) {
+	srv := startFake(t)
+	db := openFake(t, srv)
+
+	tests := []any{
+		map[string]int{"a": 1},
+		struct{ A int }{1},
+		[][]int{{1}, {2}},
+		[]any{map[string]int{}},
+	}
+
+	for _, arg := range tests {
+		_, err := db.Exec("INSERT INTO t VALUES (:1)", arg)
+		if err == nil || !strings.Contains(err.Error(), "unsupported parameter type") {
+			t.Errorf("%T: expected unsupported parameter error, got %v", arg, err)
+		}
+	}
+}
+
+func TestBlobParamsUnsupported(t *testing.T) {
+	srv := startFake(t)
+
+	// SQLite and DuckDB bind JSON strings as text, so hex encoded BLOBs would be stored as text
+	for _, vendor := range []string{"SQLite", "DuckDB"} {
+		db := openDSN(t, "valentina", srv.DSN("")+"?vendor="+vendor)
+		for _, arg := range []any{[]byte("hello"), strings.NewReader("hello")} {
+			_, err := db.Exec("INSERT INTO t VALUES (:1)", arg)
+			if !errors.Is(err, vdriver.ErrNotSupported) {
+				t.Errorf("%s %T: expected ErrNotSupported, got %v", vendor, arg, err)
+			}
+		}
+	}
+	for _, req := range srv.Requests() {
+		if strings.HasPrefix(req.Query, "INSERT") {
+			t.Errorf("BLOB parameter was sent: %v", req.Params)
+		}
+	}
+}
+
+// sendingTransport closes sending when the first byte of a request body is sent.
+type sendingTransport struct {
+	once    sync.Once
+	sending chan struct{}
+}
+
+func (st *sendingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil && strings.HasSuffix(req.URL.Path, "/sql_fast") {
+		req.Body = &signalingBody{ReadCloser: req.Body, signal: func() { st.once.Do(func() { close(st.sending) }) }}
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+type signalingBody struct {
+	io.ReadCloser
+	signal func()
+}
+
+func (b *signalingBody) Read(p []byte) (int, error) {
+	b.signal()
+	return b.ReadCloser.Read(p)
+}
+
+// waitingReader only returns its content when the request is being sent.
+type waitingReader struct {
+	r       io.Reader
+	sending chan struct{}
+}
+
+func (wr *waitingReader) Read(p []byte) (int, error) {
+	select {
+	case <-wr.sending:
+		return wr.r.Read(p)
+	case <-time.After(5 * time.Second):
+		return 0, fmt.Errorf("the BLOB was read before the request was sent")
+	}
+}
+
+func TestBlobReaderStreamed(t *testing.T) {
+	srv := startFake(t)
+	srv.HandleFunc(func(req vtest.Request) (vtest.Response, bool) {
+		return vtest.Affected(1), strings.HasPrefix(req.Query, "UPDATE")
+	})
+
+	st := sendingTransport{sending: make(chan struct{})}
+	db := sql.OpenDB(vdriver.NewConnector(vdriver.VendorValentina, vdriver.Config{
+		User:     "sa",
+		Password: "sa",
+		Host:     srv.Host(),
+		Port:     srv.Port(),
+	}, vdriver.WithTransport(&st)))
+	defer db.Close()
+
+	blob := strings.Repeat("\x00\xffHi", 64<<10)
+	if _, err := db.Exec("UPDATE docs SET data = :1 WHERE RecID = :2", &waitingReader{r: strings.NewReader(blob), sending: st.sending}, 1); err != nil {
+		t.Fatalf("failed to exec: %v", err)
+	}
+
+	reqs := srv.Requests()
+	last := reqs[len(reqs)-1]
+	if len(last.Params) != 2 || last.Params[0] != hex.EncodeToString([]byte(blob)) || jsonString(last.Params[1]) != "1" {
+		t.Errorf("unexpected params of %d bytes", len(jsonString(last.Params[0])))
+	}
+}
+
+func TestBlobReaderReplayed(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("SELECT RecID FROM docs WHERE data = :1", vtest.Table([]string{"RecID"}, []any{1}))
+
+	db := openDSN(t, "valentina", srv.DSN("")+"?relogin=true")
+	db.SetMaxOpenConns(1)
+	if err := db.Ping(); err != nil {
+		t.Fatalf("failed to ping: %v", err)
+	}
+
+	// Seekable readers are rewound, so the query can be sent again with a new session
+	srv.ExpireSessions()
+	var id int
+	if err := db.QueryRow("SELECT RecID FROM docs WHERE data = :1", strings.NewReader("Hi")).Scan(&id); err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	reqs := srv.Requests()
+	if got := reqs[len(reqs)-1].Params; len(got) != 1 || got[0] != "4869" {
+		t.Errorf("params are %v, expected [4869]", got)
+	}
+
+	// Other readers can only be sent once
+	srv.ExpireSessions()
+	err := db.QueryRow("SELECT RecID FROM docs WHERE data = :1", io.MultiReader(strings.NewReader("Hi"))).Scan(&id)
+	if err == nil || !strings.Contains(err.Error(), "can't be rewound") {
+		t.Errorf("expected an error for the replayed reader, got %v", err)
+	}
+}
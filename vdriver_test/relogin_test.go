@@ -0,0 +1,195 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vdriver_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/louis77/valentina-go/vdriver"
+	"github.com/louis77/valentina-go/vdriver/vtest"
+)
+
+func TestReloginReplaysReads(t *testing.T) {
+	srv := startFake(t)
+	db := openDSN(t, "valentina", srv.DSN("")+"?relogin=true&dateFormat=kMDY")
+	ctx := context.Background()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("failed to get connection: %v", err)
+	}
+	defer conn.Close()
+	if err := conn.PingContext(ctx); err != nil {
+		t.Fatalf("failed to ping: %v", err)
+	}
+
+	srv.ExpireSessions()
+
+	var version string
+	if err := conn.QueryRowContext(ctx, "SELECT version()").Scan(&version); err != nil {
+		t.Fatalf("failed to query after session expired: %v", err)
+	}
+
+	session, err := vdriver.SessionInfo(conn)
+	if err != nil {
+		t.Fatalf("failed to get session info: %v", err)
+	}
+	if session.Refreshes != 1 || srv.Logins() != 2 {
+		t.Errorf("expected 1 refresh and 2 logins, got %d and %d", session.Refreshes, srv.Logins())
+	}
+	if v, _ := srv.Property(session.ID, "DateTimeFormat"); v != "kMDY" {
+		t.Errorf("DateTimeFormat of the new session is %v, expected kMDY", v)
+	}
+}
+
+func TestReloginKeepsWritesStrict(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("DELETE FROM t", vtest.Affected(1))
+	db := openDSN(t, "valentina", srv.DSN("")+"?relogin=true")
+	ctx := context.Background()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("failed to get connection: %v", err)
+	}
+	defer conn.Close()
+	if err := conn.PingContext(ctx); err != nil {
+		t.Fatalf("failed to ping: %v", err)
+	}
+
+	srv.ExpireSessions()
+
+	_, err = conn.ExecContext(ctx, "DELETE FROM t")
+	if !vdriver.IsSessionExpired(err) || errors.Is(err, driver.ErrBadConn) {
+		t.Fatalf("expected an expired session error without driver.ErrBadConn, got %v", err)
+	}
+	// The fake server only records requests of existing sessions
+	for _, q := range queries(srv) {
+		if q == "DELETE FROM t" {
+			t.Errorf("write was replayed")
+		}
+	}
+
+	// The connection logged in again and can be used
+	if _, err := conn.ExecContext(ctx, "DELETE FROM t"); err != nil {
+		t.Errorf("failed to exec after relogin: %v", err)
+	}
+}
+
+func TestReloginKeepsSelectWritesStrict(t *testing.T) {
+	srv := startFake(t)
+	srv.HandleFunc(func(req vtest.Request) (vtest.Response, bool) {
+		return vtest.Table([]string{"n"}, []any{1}), req.Query != "SELECT version()"
+	})
+	db := openDSN(t, "valentina", srv.DSN("")+"?relogin=true")
+	ctx := context.Background()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("failed to get connection: %v", err)
+	}
+	defer conn.Close()
+
+	// Look up the column types of t, so the lookup doesn't log in again before the query
+	var n int
+	if err := conn.QueryRowContext(ctx, "SELECT n FROM t").Scan(&n); err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+
+	writes := []string{
+		"SELECT * INTO t2 FROM t",
+		"select a, b into u from t where c = 'x'",
+		"SELECT * FROM t FOR UPDATE",
+		"SELECT nextval('seq')",
+	}
+	for _, query := range writes {
+		if err := conn.PingContext(ctx); err != nil {
+			t.Fatalf("failed to ping: %v", err)
+		}
+		srv.ExpireSessions()
+
+		rows, err := conn.QueryContext(ctx, query)
+		if err == nil {
+			rows.Close()
+		}
+		if !vdriver.IsSessionExpired(err) {
+			t.Errorf("%s: expected an expired session error, got %v", query, err)
+		}
+		for _, q := range queries(srv) {
+			if q == query {
+				t.Errorf("%s: was replayed", query)
+			}
+		}
+	}
+
+	// Identifiers, literals and comments don't make a SELECT a write
+	srv.ExpireSessions()
+	if err := conn.QueryRowContext(ctx, `SELECT "into", t.update FROM t WHERE a = 'DELETE' -- INSERT`).Scan(&n); err != nil {
+		t.Errorf("failed to replay read: %v", err)
+	}
+}
+
+func TestReloginDisabled(t *testing.T) {
+	srv := startFake(t)
+	db := openFake(t, srv)
+	ctx := context.Background()
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("failed to get connection: %v", err)
+	}
+	defer conn.Close()
+	if err := conn.PingContext(ctx); err != nil {
+		t.Fatalf("failed to ping: %v", err)
+	}
+
+	srv.ExpireSessions()
+
+	var version string
+	if err := conn.QueryRowContext(ctx, "SELECT version()").Scan(&version); !errors.Is(err, driver.ErrBadConn) {
+		t.Errorf("expected driver.ErrBadConn, got %v", err)
+	}
+	if srv.Logins() != 1 {
+		t.Errorf("expected no relogin, got %d logins", srv.Logins())
+	}
+}
+
+func TestReloginKeepsTransactions(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("BEGIN TRANSACTION", vtest.NoResult())
+	srv.Handle("INSERT INTO t VALUES (1)", vtest.Affected(1))
+	srv.Handle("ROLLBACK", vtest.NoResult())
+	db := openDSN(t, "vsqlite", srv.DSN("")+"?relogin=true")
+	ctx := context.Background()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to begin transaction: %v", err)
+	}
+
+	srv.ExpireSessions()
+
+	if _, err := tx.ExecContext(ctx, "INSERT INTO t VALUES (1)"); !vdriver.IsSessionExpired(err) {
+		t.Fatalf("expected an expired session error, got %v", err)
+	}
+	// The transaction is lost with its session, nothing may run outside of it
+	if _, err := tx.ExecContext(ctx, "INSERT INTO t VALUES (1)"); err == nil {
+		t.Fatal("statement ran outside of the transaction")
+	}
+	if err := tx.Rollback(); err == nil {
+		t.Error("rollback of the lost transaction succeeded")
+	}
+	if srv.Logins() != 1 {
+		t.Errorf("expected no relogin within the transaction, got %d logins", srv.Logins())
+	}
+	for _, q := range queries(srv) {
+		if q == "INSERT INTO t VALUES (1)" {
+			t.Errorf("insert was executed")
+		}
+	}
+}
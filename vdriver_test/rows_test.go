@@ -0,0 +1,259 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vdriver_test
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/louis77/valentina-go/vdriver"
+	"github.com/louis77/valentina-go/vdriver/vtest"
+)
+
+func TestExactNumbers(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("SELECT id, big, price, ratio, weight, fee, 0.5 FROM accounts",
+		vtest.Table([]string{"id", "big", "price", "ratio", "weight", "fee", "0.5"},
+			[]any{int64(9007199254740993), uint64(18446744073709551615), json.Number("12345678901234.57"), 1.5, 3, 100, 0.5}))
+	srv.Handle("SHOW COLUMNS FROM accounts",
+		vtest.Table([]string{"fld_name", "fld_type_str"},
+			[]any{"id", "LLONG"},
+			[]any{"big", "ULLONG"},
+			[]any{"price", "MONEY"},
+			[]any{"ratio", "DOUBLE"},
+			[]any{"weight", "DOUBLE"},
+			[]any{"fee", "DECIMAL"},
+		))
+
+	db := openFake(t, srv)
+	values := make([]any, 7)
+	pointers := make([]any, len(values))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	if err := db.QueryRow("SELECT id, big, price, ratio, weight, fee, 0.5 FROM accounts").Scan(pointers...); err != nil {
+		t.Fatalf("failed to scan: %v", err)
+	}
+
+	// Integral literals of DOUBLE and DECIMAL columns keep the type of the column
+	want := []any{int64(9007199254740993), "18446744073709551615", "12345678901234.57", 1.5, float64(3), "100", 0.5}
+	for i := range want {
+		if values[i] != want[i] {
+			t.Errorf("field %d is %#v, expected %#v", i, values[i], want[i])
+		}
+	}
+}
+
+func TestStreamingRows(t *testing.T) {
+	const n = 50000
+
+	srv := startFake(t)
+	records := make([][]any, n)
+	for i := range records {
+		records[i] = []any{i, "some text to make the response larger"}
+	}
+	srv.Handle("SELECT id, txt FROM big", vtest.Table([]string{"id", "txt"}, records...))
+
+	wt := withholdingTransport{release: make(chan struct{})}
+	db := sql.OpenDB(vdriver.NewConnector(vdriver.VendorValentina, vdriver.Config{
+		User:     "sa",
+		Password: "sa",
+		Host:     srv.Host(),
+		Port:     srv.Port(),
+	}, vdriver.WithTransport(&wt)))
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	// The end of the response is only sent once the first row was returned
+	rows, err := db.Query("SELECT id, txt FROM big")
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	count := 0
+	for rows.Next() {
+		if count == 0 {
+			close(wt.release)
+		}
+		var id int
+		var txt string
+		if err := rows.Scan(&id, &txt); err != nil {
+			t.Fatalf("failed to scan: %v", err)
+		}
+		if id != count {
+			t.Fatalf("row %d has id %d", count, id)
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("rows error: %v", err)
+	}
+	if count != n {
+		t.Fatalf("got %d rows, expected %d", count, n)
+	}
+
+	// Stop early, the connection must still be usable
+	rows, err = db.Query("SELECT id, txt FROM big")
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	if !rows.Next() {
+		t.Fatalf("expected a row: %v", rows.Err())
+	}
+	if err := rows.Close(); err != nil {
+		t.Fatalf("failed to close rows: %v", err)
+	}
+
+	var version string
+	if err := db.QueryRow("SELECT version()").Scan(&version); err != nil {
+		t.Fatalf("failed to query after closing rows early: %v", err)
+	}
+}
+
+// withholdingTransport holds back all but the first 4 KiB of sql_fast responses until
+// release is closed.
+type withholdingTransport struct {
+	release chan struct{}
+}
+
+func (wt *withholdingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err == nil && strings.HasSuffix(req.URL.Path, "/sql_fast") {
+		resp.Body = &withholdingBody{ReadCloser: resp.Body, release: wt.release, remaining: 4 << 10}
+	}
+	return resp, err
+}
+
+type withholdingBody struct {
+	io.ReadCloser
+	release   chan struct{}
+	remaining int
+}
+
+func (b *withholdingBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		select {
+		case <-b.release:
+		case <-time.After(5 * time.Second):
+			return 0, errors.New("the whole response was read before the first row was returned")
+		}
+		return b.ReadCloser.Read(p)
+	}
+
+	if len(p) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= n
+	return n, err
+}
+
+// exclusiveTransport counts the sql_fast requests that were sent while the body of an
+// earlier response was still open.
+type exclusiveTransport struct {
+	mu          sync.Mutex
+	open        int
+	overlapping int
+}
+
+func (et *exclusiveTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !strings.HasSuffix(req.URL.Path, "/sql_fast") {
+		return http.DefaultTransport.RoundTrip(req)
+	}
+	et.mu.Lock()
+	if et.open > 0 {
+		et.overlapping++
+	}
+	et.mu.Unlock()
+
+	resp, err := http.DefaultTransport.RoundTrip(req)
+	if err == nil {
+		et.mu.Lock()
+		et.open++
+		et.mu.Unlock()
+		resp.Body = &closeNotifyingBody{ReadCloser: resp.Body, close: func() {
+			et.mu.Lock()
+			et.open--
+			et.mu.Unlock()
+		}}
+	}
+	return resp, err
+}
+
+type closeNotifyingBody struct {
+	io.ReadCloser
+	once  sync.Once
+	close func()
+}
+
+func (b *closeNotifyingBody) Close() error {
+	b.once.Do(b.close)
+	return b.ReadCloser.Close()
+}
+
+func TestStatementsWhileStreaming(t *testing.T) {
+	const n = 20000
+
+	srv := startFake(t)
+	records := make([][]any, n)
+	for i := range records {
+		records[i] = []any{i, "some text to make the response larger"}
+	}
+	srv.Handle("SELECT id, txt FROM big", vtest.Table([]string{"id", "txt"}, records...))
+	srv.Handle("UPDATE big SET seen = 1", vtest.Affected(n))
+
+	var et exclusiveTransport
+	db := sql.OpenDB(vdriver.NewConnector(vdriver.VendorValentina, vdriver.Config{
+		User:     "sa",
+		Password: "sa",
+		Host:     srv.Host(),
+		Port:     srv.Port(),
+	}, vdriver.WithTransport(&et)))
+	defer db.Close()
+
+	conn, err := db.Conn(t.Context())
+	if err != nil {
+		t.Fatalf("failed to get connection: %v", err)
+	}
+	defer conn.Close()
+
+	// database/sql allows statements on the same Conn or Tx within a rows.Next loop
+	rows, err := conn.QueryContext(t.Context(), "SELECT id, txt FROM big")
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	count := 0
+	for rows.Next() {
+		if count == 10 {
+			if _, err := conn.ExecContext(t.Context(), "UPDATE big SET seen = 1"); err != nil {
+				t.Fatalf("failed to exec while streaming: %v", err)
+			}
+		}
+		var id int
+		var txt string
+		if err := rows.Scan(&id, &txt); err != nil {
+			t.Fatalf("failed to scan: %v", err)
+		}
+		if id != count {
+			t.Fatalf("row %d has id %d", count, id)
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("rows error: %v", err)
+	}
+	if count != n {
+		t.Errorf("got %d rows, expected %d", count, n)
+	}
+	if et.overlapping > 0 {
+		t.Errorf("%d requests were sent while a response was streamed", et.overlapping)
+	}
+}
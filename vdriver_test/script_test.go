@@ -0,0 +1,301 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vdriver_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/louis77/valentina-go/vdriver"
+	"github.com/louis77/valentina-go/vdriver/vtest"
+)
+
+func TestExecScript(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("CREATE TABLE t (id LONG, name VARCHAR(20))", vtest.NoResult())
+	srv.Handle("INSERT INTO t VALUES (:1, :2)", vtest.Affected(1))
+	srv.Handle("UPDATE t SET name = ':3; ?' WHERE id = :1", vtest.Affected(2))
+
+	db := openFake(t, srv)
+	result, err := db.Exec(`CREATE TABLE t (id LONG, name VARCHAR(20));
+		-- Seed; the table
+		INSERT INTO t VALUES (:1, :2);
+		INSERT INTO t VALUES (:3, :2);
+		UPDATE t SET name = ':3; ?' WHERE id = :3;
+		;`, 1, "Alice", 2)
+	if err != nil {
+		t.Fatalf("failed to exec script: %v", err)
+	}
+	if n, _ := result.RowsAffected(); n != 4 {
+		t.Errorf("affected rows are %d, expected 4", n)
+	}
+
+	var params [][]any
+	for _, req := range srv.Requests() {
+		if strings.HasPrefix(req.Query, "INSERT") || strings.HasPrefix(req.Query, "UPDATE") {
+			var p []any
+			for _, v := range req.Params {
+				p = append(p, strings.Trim(strings.TrimSpace(jsonString(v)), `"`))
+			}
+			params = append(params, p)
+		}
+	}
+	want := [][]any{{"1", "Alice"}, {"2", "Alice"}, {"2"}}
+	if !reflect.DeepEqual(params, want) {
+		t.Errorf("params are %v, expected %v", params, want)
+	}
+}
+
+func TestExecScriptPositional(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("INSERT INTO t VALUES (?, ?)", vtest.Affected(1))
+	srv.Handle("DELETE FROM t WHERE id = ?", vtest.Affected(1))
+
+	db := openFake(t, srv)
+	if _, err := db.Exec("INSERT INTO t VALUES (?, ?); DELETE FROM t WHERE id = ?", 1, "a", 2); err != nil {
+		t.Fatalf("failed to exec script: %v", err)
+	}
+
+	reqs := srv.Requests()
+	if last := reqs[len(reqs)-1]; len(last.Params) != 1 || jsonString(last.Params[0]) != "2" {
+		t.Errorf("unexpected params of the last statement: %v", last.Params)
+	}
+}
+
+func TestExecScriptDuckDBSlices(t *testing.T) {
+	srv := startFake(t)
+	srv.HandleFunc(func(req vtest.Request) (vtest.Response, bool) {
+		return vtest.Affected(1), true
+	})
+
+	// The colons of slices and struct literals are no placeholders
+	db := openDSN(t, "vduckdb", srv.DSN(""))
+	if _, err := db.Exec("SELECT l[1:2] FROM t; SELECT {'a':1}, ?", 3); err != nil {
+		t.Fatalf("failed to exec script: %v", err)
+	}
+
+	var queries []string
+	for _, req := range srv.Requests() {
+		if strings.HasPrefix(req.Query, "SELECT") {
+			queries = append(queries, req.Query)
+		}
+	}
+	want := []string{"SELECT l[1:2] FROM t", "SELECT {'a':1}, ?"}
+	if !reflect.DeepEqual(queries, want) {
+		t.Errorf("statements are %q, expected %q", queries, want)
+	}
+}
+
+func TestExecScriptError(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("INSERT INTO t VALUES (1)", vtest.Affected(1))
+	srv.Handle("INSERT INTO u VALUES (1)", vtest.Failure(500, "Table u not found"))
+
+	db := openFake(t, srv)
+	_, err := db.Exec("INSERT INTO t VALUES (1); INSERT INTO u VALUES (1); INSERT INTO t VALUES (1)")
+	if err == nil || !strings.HasPrefix(err.Error(), "statement 2: ") {
+		t.Fatalf("expected an error of statement 2, got %v", err)
+	}
+	var verr *vdriver.Error
+	if !errors.As(err, &verr) || verr.Query != "INSERT INTO u VALUES (1)" {
+		t.Errorf("expected the server error, got %#v", err)
+	}
+
+	inserts := 0
+	for _, q := range queries(srv) {
+		if q == "INSERT INTO t VALUES (1)" {
+			inserts++
+		}
+	}
+	if inserts != 1 {
+		t.Errorf("expected the script to stop at the failed statement, got %d inserts", inserts)
+	}
+}
+
+func TestExecScriptSessionExpired(t *testing.T) {
+	srv := startFake(t)
+	srv.HandleFunc(func(req vtest.Request) (vtest.Response, bool) {
+		if req.Query == "INSERT INTO u VALUES (1)" {
+			srv.ExpireSessions()
+		}
+		return vtest.Affected(1), strings.HasPrefix(req.Query, "INSERT")
+	})
+
+	db := openFake(t, srv)
+	_, err := db.Exec("INSERT INTO t VALUES (1); INSERT INTO u VALUES (1); INSERT INTO v VALUES (1)")
+	if err == nil {
+		t.Fatalf("expected an error")
+	}
+
+	// database/sql must not retry the script, the first statement was executed
+	for _, q := range queries(srv)[1:] {
+		if q == "INSERT INTO t VALUES (1)" {
+			t.Errorf("script was executed again")
+		}
+	}
+}
+
+func TestQueryMultipleResultSets(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("SELECT id FROM t", vtest.Table([]string{"id"}, []any{1}, []any{2}))
+	srv.Handle("UPDATE t SET id = id + 1", vtest.Affected(2))
+	srv.Handle("SET PROPERTY x OF DATABASE TO 1", vtest.NoResult())
+	srv.Handle("SELECT name FROM u WHERE id = :1", vtest.Table([]string{"name"}, []any{"Alice"}))
+
+	db := openFake(t, srv)
+	rows, err := db.Query("SELECT id FROM t; UPDATE t SET id = id + 1; SET PROPERTY x OF DATABASE TO 1; SELECT name FROM u WHERE id = :2", 0, 7)
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	defer rows.Close()
+
+	var sets [][]any
+	for {
+		var set []any
+		for rows.Next() {
+			var v any
+			if err := rows.Scan(&v); err != nil {
+				t.Fatalf("failed to scan: %v", err)
+			}
+			set = append(set, v)
+		}
+		sets = append(sets, set)
+		if !rows.NextResultSet() {
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("failed to iterate: %v", err)
+	}
+
+	want := [][]any{{int64(1), int64(2)}, {int64(2)}, nil, {"Alice"}}
+	if !reflect.DeepEqual(sets, want) {
+		t.Errorf("result sets are %v, expected %v", sets, want)
+	}
+
+	reqs := srv.Requests()
+	if last := reqs[len(reqs)-1]; len(last.Params) != 1 || jsonString(last.Params[0]) != "7" {
+		t.Errorf("unexpected params of the last statement: %v", last.Params)
+	}
+}
+
+func TestQueryResultSetError(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("SELECT 1", vtest.Table([]string{"1"}, []any{1}))
+	srv.Handle("SELECT x", vtest.Failure(500, "Syntax error near x"))
+
+	db := openFake(t, srv)
+	rows, err := db.Query("SELECT 1; SELECT x")
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+	}
+	if rows.NextResultSet() {
+		t.Fatalf("expected the second statement to fail")
+	}
+	if err := rows.Err(); err == nil || !strings.HasPrefix(err.Error(), "statement 2: ") || !vdriver.IsSyntaxError(err) {
+		t.Errorf("expected a syntax error of statement 2, got %v", err)
+	}
+}
+
+// jsonString returns the JSON text of a parameter the fake server received.
+func jsonString(v any) string {
+	return fmt.Sprint(v)
+}
+
+func TestExecScriptBlocks(t *testing.T) {
+	tests := []struct {
+		script string
+		want   []string
+	}{
+		{
+			"CREATE TRIGGER tr AFTER INSERT ON t BEGIN UPDATE x SET a=1; UPDATE y SET b=2; END;",
+			[]string{"CREATE TRIGGER tr AFTER INSERT ON t BEGIN UPDATE x SET a=1; UPDATE y SET b=2; END;"},
+		},
+		{
+			"CREATE TRIGGER tr AFTER INSERT ON t BEGIN UPDATE x SET a=1; END; INSERT INTO t VALUES (1)",
+			[]string{"CREATE TRIGGER tr AFTER INSERT ON t BEGIN UPDATE x SET a=1; END", "INSERT INTO t VALUES (1)"},
+		},
+		{
+			"CREATE PROCEDURE p() BEGIN IF x THEN UPDATE t SET a=1; END IF; UPDATE t SET b=CASE WHEN c THEN 1 ELSE 2 END; END; SELECT 1",
+			[]string{"CREATE PROCEDURE p() BEGIN IF x THEN UPDATE t SET a=1; END IF; UPDATE t SET b=CASE WHEN c THEN 1 ELSE 2 END; END", "SELECT 1"},
+		},
+		{
+			"BEGIN TRANSACTION; UPDATE t SET a=1; END",
+			[]string{"BEGIN TRANSACTION", "UPDATE t SET a=1", "END"},
+		},
+		{
+			"UPDATE events SET begin = 1 WHERE id = 2; DELETE FROM log",
+			[]string{"UPDATE events SET begin = 1 WHERE id = 2", "DELETE FROM log"},
+		},
+		{
+			"CREATE TABLE events (id INT, begin INT); SELECT begin FROM events",
+			[]string{"CREATE TABLE events (id INT, begin INT)", "SELECT begin FROM events"},
+		},
+		{
+			"CREATE OR REPLACE FUNCTION f() RETURNS INT AS BEGIN RETURN 1; END; UPDATE events SET begin = f()",
+			[]string{"CREATE OR REPLACE FUNCTION f() RETURNS INT AS BEGIN RETURN 1; END", "UPDATE events SET begin = f()"},
+		},
+		{
+			"SELECT $$a;b$$",
+			[]string{"SELECT $$a;b$$"},
+		},
+		{
+			"SELECT $fn$ x; $$ $fn$; SELECT t.end FROM t",
+			[]string{"SELECT $fn$ x; $$ $fn$", "SELECT t.end FROM t"},
+		},
+	}
+
+	for _, tt := range tests {
+		srv := startFake(t)
+		srv.HandleFunc(func(req vtest.Request) (vtest.Response, bool) {
+			return vtest.NoResult(), true
+		})
+
+		db := openFake(t, srv)
+		if _, err := db.Exec(tt.script); err != nil {
+			t.Fatalf("%q: failed to exec: %v", tt.script, err)
+		}
+		if got := queries(srv); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%q: executed %q, expected %q", tt.script, got, tt.want)
+		}
+	}
+}
+
+func TestQueryScriptCloseRunsRemaining(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("SELECT id FROM t", vtest.Table([]string{"id"}, []any{1}))
+	srv.Handle("INSERT INTO t VALUES (2)", vtest.Affected(1))
+	srv.Handle("UPDATE t SET id = 3", vtest.Affected(2))
+	srv.Handle("INSERT INTO u VALUES (1)", vtest.Failure(500, "Table u not found"))
+
+	db := openFake(t, srv)
+
+	var id int
+	if err := db.QueryRow("SELECT id FROM t; INSERT INTO t VALUES (2); UPDATE t SET id = 3").Scan(&id); err != nil {
+		t.Fatalf("failed to query script: %v", err)
+	}
+	want := []string{"SELECT id FROM t", "INSERT INTO t VALUES (2)", "UPDATE t SET id = 3"}
+	if got := queries(srv); !reflect.DeepEqual(got, want) {
+		t.Errorf("executed %q, expected %q", got, want)
+	}
+
+	rows, err := db.Query("SELECT id FROM t; INSERT INTO u VALUES (1); INSERT INTO t VALUES (2)")
+	if err != nil {
+		t.Fatalf("failed to query script: %v", err)
+	}
+	if err := rows.Close(); err == nil || !strings.HasPrefix(err.Error(), "statement 2: ") {
+		t.Errorf("expected Close to fail with statement 2, got %v", err)
+	}
+	if got := queries(srv); len(got) != len(want)+2 {
+		t.Errorf("executed %q after the failed statement", got[len(want):])
+	}
+}
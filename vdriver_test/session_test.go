@@ -0,0 +1,189 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vdriver_test
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	_ "github.com/louis77/valentina-go/vdriver"
+	"github.com/louis77/valentina-go/vdriver/vtest"
+)
+
+// startFake starts a fake Valentina server that is shut down when the test ends.
+func startFake(t *testing.T) *vtest.Server {
+	t.Helper()
+
+	srv := vtest.NewServer()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// openFake opens a Valentina database on srv that is closed when the test ends.
+func openFake(t *testing.T, srv *vtest.Server) *sql.DB {
+	t.Helper()
+	return openDSN(t, "valentina", srv.DSN(""))
+}
+
+// openDSN opens a database that is closed when the test ends.
+func openDSN(t *testing.T, driverName, dsn string) *sql.DB {
+	t.Helper()
+
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := db.Close(); err != nil {
+			t.Errorf("failed to close database: %v", err)
+		}
+	})
+	return db
+}
+
+func TestSessionLifecycle(t *testing.T) {
+	srv := startFake(t)
+
+	db, err := sql.Open("valentina", srv.DSN(""))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		t.Fatalf("failed to ping: %v", err)
+	}
+
+	sessions := srv.Sessions()
+	if len(sessions) != 1 {
+		t.Fatalf("expected 1 session, got %d", len(sessions))
+	}
+	if v, _ := srv.Property(sessions[0], "DateTimeFormat"); v != "kYMD" {
+		t.Fatalf("DateTimeFormat is %v, expected kYMD", v)
+	}
+	if v, _ := srv.Property(sessions[0], "DateSeparator"); v != "-" {
+		t.Fatalf("DateSeparator is %v, expected -", v)
+	}
+
+	if err := db.Close(); err != nil {
+		t.Fatalf("failed to close database: %v", err)
+	}
+	if n := len(srv.Sessions()); n != 0 {
+		t.Fatalf("expected all sessions to be removed, %d left", n)
+	}
+}
+
+func TestSessionAuthFailed(t *testing.T) {
+	srv := startFake(t)
+	srv.AddUser("sa", "secret")
+
+	db := openFake(t, srv)
+	err := db.Ping()
+	if err == nil {
+		t.Fatal("ping should have failed")
+	}
+	if !strings.Contains(err.Error(), vtest.MsgAuthFailed) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSessionExpired(t *testing.T) {
+	srv := startFake(t)
+
+	db := openFake(t, srv)
+	db.SetMaxOpenConns(1)
+	if err := db.Ping(); err != nil {
+		t.Fatalf("failed to ping: %v", err)
+	}
+
+	srv.ExpireSessions()
+
+	// database/sql retries with a new connection once the driver reports driver.ErrBadConn
+	var version string
+	if err := db.QueryRow("SELECT version()").Scan(&version); err != nil {
+		t.Fatalf("failed to query after session expired: %v", err)
+	}
+	if n := srv.Logins(); n != 2 {
+		t.Fatalf("expected 2 logins, got %d", n)
+	}
+}
+
+func TestQueryRows(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("SELECT id, name FROM customers WHERE id > :1",
+		vtest.Table([]string{"id", "name"},
+			[]any{1, "Alice"},
+			[]any{2, nil},
+		))
+
+	db := openFake(t, srv)
+	rows, err := db.Query("SELECT id, name FROM customers WHERE id > :1", 0)
+	if err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		t.Fatalf("failed to get columns: %v", err)
+	}
+	if strings.Join(columns, ",") != "id,name" {
+		t.Fatalf("unexpected columns: %v", columns)
+	}
+
+	var got []string
+	for rows.Next() {
+		var id int
+		var name sql.NullString
+		if err := rows.Scan(&id, &name); err != nil {
+			t.Fatalf("failed to scan: %v", err)
+		}
+		got = append(got, name.String)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("rows error: %v", err)
+	}
+	if len(got) != 2 || got[0] != "Alice" || got[1] != "" {
+		t.Fatalf("unexpected rows: %q", got)
+	}
+
+	for _, req := range srv.Requests() {
+		if req.Query == "SELECT id, name FROM customers WHERE id > :1" && (req.Vendor != "Valentina" || len(req.Params) != 1) {
+			t.Fatalf("unexpected request: %+v", req)
+		}
+	}
+}
+
+func TestQueryError(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("SELEC 1", vtest.Failure(400, "Syntax error near 'SELEC'"))
+
+	db := openFake(t, srv)
+	_, err := db.Query("SELEC 1")
+	if err == nil {
+		t.Fatal("query should have failed")
+	}
+	if !strings.Contains(err.Error(), "Syntax error") {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestExecAffectedRows(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("DELETE FROM customers", vtest.Affected(3))
+
+	db := openFake(t, srv)
+	res, err := db.Exec("DELETE FROM customers")
+	if err != nil {
+		t.Fatalf("failed to exec: %v", err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		t.Fatalf("failed to get rows affected: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("expected 3 affected rows, got %d", n)
+	}
+}
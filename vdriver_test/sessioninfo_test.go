@@ -0,0 +1,142 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vdriver_test
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/louis77/valentina-go/vdriver"
+)
+
+func TestSessionInfo(t *testing.T) {
+	srv := startFake(t)
+	db := openDSN(t, "vsqlite", srv.DSN("testdb"))
+	ctx := context.Background()
+
+	start := time.Now()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("failed to get connection: %v", err)
+	}
+	defer conn.Close()
+	if err := conn.PingContext(ctx); err != nil {
+		t.Fatalf("failed to ping: %v", err)
+	}
+
+	session, err := vdriver.SessionInfo(conn)
+	if err != nil {
+		t.Fatalf("failed to get session info: %v", err)
+	}
+
+	if ids := srv.Sessions(); len(ids) != 1 || session.ID != ids[0] {
+		t.Errorf("session ID is %q, expected one of %v", session.ID, ids)
+	}
+	if session.Vendor != vdriver.VendorSQLite || session.Database != "testdb" {
+		t.Errorf("unexpected vendor or database: %+v", session)
+	}
+	if session.Created.Before(start) || session.LastUsed.Before(session.Created) {
+		t.Errorf("unexpected times: %+v", session)
+	}
+	if session.Refreshes != 0 {
+		t.Errorf("refreshes are %d, expected 0", session.Refreshes)
+	}
+}
+
+func TestDriverStats(t *testing.T) {
+	srv := startFake(t)
+	before := vdriver.Stats()
+
+	db, err := sql.Open("valentina", srv.DSN(""))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	db.SetMaxOpenConns(1)
+	if err := db.Ping(); err != nil {
+		t.Fatalf("failed to ping: %v", err)
+	}
+
+	srv.ExpireSessions()
+	var version string
+	if err := db.QueryRow("SELECT version()").Scan(&version); err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatalf("failed to close database: %v", err)
+	}
+
+	after := vdriver.Stats()
+	delta := vdriver.DriverStats{
+		SessionsCreated: after.SessionsCreated - before.SessionsCreated,
+		SessionsClosed:  after.SessionsClosed - before.SessionsClosed,
+		SessionsExpired: after.SessionsExpired - before.SessionsExpired,
+		OpenSessions:    after.OpenSessions - before.OpenSessions,
+	}
+	want := vdriver.DriverStats{SessionsCreated: 2, SessionsClosed: 1, SessionsExpired: 1}
+	if delta != want {
+		t.Errorf("stats changed by %+v, expected %+v", delta, want)
+	}
+	if after.Requests <= before.Requests {
+		t.Errorf("requests were not counted")
+	}
+}
+
+func TestSessionRefreshedWhenIdle(t *testing.T) {
+	srv := startFake(t)
+	db := openDSN(t, "valentina", srv.DSN("")+"?sessionTimeout=200ms&dateFormat=kDMY")
+	db.SetMaxOpenConns(1)
+	ctx := context.Background()
+	before := vdriver.Stats()
+
+	if err := db.Ping(); err != nil {
+		t.Fatalf("failed to ping: %v", err)
+	}
+	first := srv.Sessions()
+
+	// Not idle long enough, the session is kept
+	if err := db.Ping(); err != nil {
+		t.Fatalf("failed to ping: %v", err)
+	}
+	if got := srv.Sessions(); len(got) != 1 || got[0] != first[0] {
+		t.Fatalf("session was replaced too early: %v, %v", first, got)
+	}
+
+	// The server removes the idle session, the next query must not notice
+	time.Sleep(200 * time.Millisecond)
+	srv.ExpireSessions()
+	sent := len(srv.Requests())
+
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		t.Fatalf("failed to get connection: %v", err)
+	}
+	defer conn.Close()
+
+	var version string
+	if err := conn.QueryRowContext(ctx, "SELECT version()").Scan(&version); err != nil {
+		t.Fatalf("failed to query: %v", err)
+	}
+	for _, req := range srv.Requests()[sent:] {
+		if req.Query == "SELECT version()" && req.SessionID == first[0] {
+			t.Errorf("query was sent with the idle session")
+		}
+	}
+
+	session, err := vdriver.SessionInfo(conn)
+	if err != nil {
+		t.Fatalf("failed to get session info: %v", err)
+	}
+	if session.Refreshes != 1 {
+		t.Errorf("refreshes are %d, expected 1", session.Refreshes)
+	}
+	if v, _ := srv.Property(session.ID, "DateTimeFormat"); v != "kDMY" {
+		t.Errorf("DateTimeFormat of the new session is %v, expected kDMY", v)
+	}
+	if n := vdriver.Stats().Refreshes - before.Refreshes; n != 1 {
+		t.Errorf("driver refreshes changed by %d, expected 1", n)
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vdriver_test
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/louis77/valentina-go/vdriver/vtest"
+	"github.com/louis77/valentina-go/vsql"
+)
+
+func TestParseTime(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("SELECT born, wakeup, updated, name, now() AS ts FROM people",
+		vtest.Table([]string{"born", "wakeup", "updated", "name", "ts"},
+			[]any{"1999-12-31", "07:30:00:250", "2025-01-02 17:56:39:400", "2025-01-02 17:56:39:400", "2025-01-02 17:56:39:400"},
+			[]any{nil, nil, nil, "Bob", "2025-01-02"},
+		))
+	srv.Handle("SHOW COLUMNS FROM people",
+		vtest.Table([]string{"fld_name", "fld_type_str"},
+			[]any{"born", "DATE"},
+			[]any{"wakeup", "TIME"},
+			[]any{"updated", "DATETIME"},
+			[]any{"name", "VARCHAR(40)"},
+		))
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+
+	tests := []struct {
+		dsn string
+		loc *time.Location
+	}{
+		{"?parseTime=true", time.UTC},
+		{"?parseTime=true&loc=Europe%2FBerlin", berlin},
+	}
+
+	for _, tt := range tests {
+		db := openDSN(t, "valentina", srv.DSN("")+tt.dsn)
+
+		rows, err := db.Query("SELECT born, wakeup, updated, name, now() AS ts FROM people")
+		if err != nil {
+			t.Fatalf("failed to query: %v", err)
+		}
+
+		types, err := rows.ColumnTypes()
+		if err != nil {
+			t.Fatalf("failed to get column types: %v", err)
+		}
+		if got := types[2].ScanType(); got != reflect.TypeFor[time.Time]() {
+			t.Errorf("updated: scan type is %v, expected time.Time", got)
+		}
+
+		var born, wakeup, updated time.Time
+		var name, ts string
+		rows.Next()
+		if err := rows.Scan(&born, &wakeup, &updated, &name, &ts); err != nil {
+			t.Fatalf("failed to scan: %v", err)
+		}
+
+		if want := time.Date(1999, 12, 31, 0, 0, 0, 0, tt.loc); !born.Equal(want) {
+			t.Errorf("born is %v, expected %v", born, want)
+		}
+		if want := time.Date(0, 1, 1, 7, 30, 0, 250_000_000, tt.loc); !wakeup.Equal(want) {
+			t.Errorf("wakeup is %v, expected %v", wakeup, want)
+		}
+		want := time.Date(2025, 1, 2, 17, 56, 39, 400_000_000, tt.loc)
+		if !updated.Equal(want) || updated.Location().String() != tt.loc.String() {
+			t.Errorf("updated is %v, expected %v", updated, want)
+		}
+		if name != "2025-01-02 17:56:39:400" || ts != name {
+			t.Errorf("name is %q and ts is %q, expected the unparsed strings", name, ts)
+		}
+
+		// NULLs stay NULL, strings of unknown fields aren't parsed
+		var nullBorn *time.Time
+		var tsString string
+		rows.Next()
+		if err := rows.Scan(&nullBorn, &wakeup, &updated, &name, &tsString); err == nil {
+			t.Errorf("expected scanning NULL into time.Time to fail")
+		}
+		if err := rows.Scan(&nullBorn, new(any), new(any), &name, &tsString); err != nil {
+			t.Fatalf("failed to scan: %v", err)
+		}
+		if nullBorn != nil || tsString != "2025-01-02" {
+			t.Errorf("got %v and %q, expected nil and the unparsed string", nullBorn, tsString)
+		}
+		rows.Close()
+	}
+}
+
+func TestParseTimeUnknownFields(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("SELECT p.note, e.at FROM people p JOIN events e ON e.person = p.id",
+		vtest.Table([]string{"note", "at"}, []any{"2025-01-02 10:00:00:000", "2025-01-02 11:00:00:000"}))
+	db := openDSN(t, "valentina", srv.DSN("")+"?parseTime=true")
+
+	// The types of joined fields are unknown, a VARCHAR may look like a date and time
+	var note, at any
+	if err := db.QueryRow("SELECT p.note, e.at FROM people p JOIN events e ON e.person = p.id").Scan(&note, &at); err != nil {
+		t.Fatalf("failed to scan: %v", err)
+	}
+	if note != "2025-01-02 10:00:00:000" || at != "2025-01-02 11:00:00:000" {
+		t.Errorf("got %#v and %#v, expected the unchanged strings", note, at)
+	}
+}
+
+func TestParseTimeDisabled(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("SELECT now()", vtest.Table([]string{"now()"}, []any{"2025-01-02 17:56:39:400"}))
+	db := openFake(t, srv)
+
+	var v any
+	if err := db.QueryRow("SELECT now()").Scan(&v); err != nil {
+		t.Fatalf("failed to scan: %v", err)
+	}
+	if v != "2025-01-02 17:56:39:400" {
+		t.Errorf("got %#v, expected the unparsed string", v)
+	}
+}
+
+func TestTimeParamLocation(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("INSERT INTO t VALUES (:1)", vtest.Affected(1))
+	db := openDSN(t, "valentina", srv.DSN("")+"?parseTime=true&loc=UTC")
+
+	ts := time.Date(2025, 3, 14, 16, 9, 26, 0, time.FixedZone("CET", 3600))
+	if _, err := db.Exec("INSERT INTO t VALUES (:1)", ts); err != nil {
+		t.Fatalf("failed to exec: %v", err)
+	}
+
+	reqs := srv.Requests()
+	if got := reqs[len(reqs)-1].Params[0]; got != "2025-03-14 15:09:26.000" {
+		t.Errorf("param is %v, expected the time in UTC", got)
+	}
+}
+
+func TestTimeRoundTripLocation(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("SELECT updated, born FROM people", vtest.Table([]string{"updated", "born"}, []any{"2025-01-02 17:56:39:400", "2025-01-02"}))
+	srv.Handle("SHOW COLUMNS FROM people", vtest.Table([]string{"fld_name", "fld_type_str"}, []any{"updated", "DATETIME"}, []any{"born", "DATE"}))
+	srv.Handle("INSERT INTO t VALUES (:1, :2)", vtest.Affected(1))
+
+	if _, err := time.LoadLocation("America/New_York"); err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+	db := openDSN(t, "valentina", srv.DSN("")+"?parseTime=true&loc=America%2FNew_York")
+
+	// Values are written back as they were read
+	var updated vsql.Time
+	var born vsql.Date
+	if err := db.QueryRow("SELECT updated, born FROM people").Scan(&updated, &born); err != nil {
+		t.Fatalf("failed to scan: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO t VALUES (:1, :2)", updated, born); err != nil {
+		t.Fatalf("failed to exec: %v", err)
+	}
+
+	reqs := srv.Requests()
+	params := reqs[len(reqs)-1].Params
+	if len(params) != 2 || params[0] != "2025-01-02 17:56:39.400" || params[1] != "2025-01-02" {
+		t.Errorf("params are %v, expected the scanned values", params)
+	}
+}
+
+func TestTimeRoundTripDefaultLocation(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("INSERT INTO events VALUES (:1)", vtest.Affected(1))
+	srv.Handle("SHOW COLUMNS FROM events", vtest.Table([]string{"fld_name", "fld_type_str"}, []any{"at", "DATETIME"}))
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("time zone database not available: %v", err)
+	}
+	db := openDSN(t, "valentina", srv.DSN("")+"?parseTime=true")
+
+	// Without loc, parameters are written in UTC, the location results are read in
+	at := time.Date(2025, 1, 2, 10, 0, 0, 0, berlin)
+	if _, err := db.Exec("INSERT INTO events VALUES (:1)", at); err != nil {
+		t.Fatalf("failed to exec: %v", err)
+	}
+	reqs := srv.Requests()
+	param := reqs[len(reqs)-1].Params[0]
+	if param != "2025-01-02 09:00:00.000" {
+		t.Fatalf("param is %v, expected the time in UTC", param)
+	}
+
+	srv.Handle("SELECT at FROM events", vtest.Table([]string{"at"}, []any{param}))
+	var got time.Time
+	if err := db.QueryRow("SELECT at FROM events").Scan(&got); err != nil {
+		t.Fatalf("failed to scan: %v", err)
+	}
+	if !got.Equal(at) {
+		t.Errorf("read %v, expected %v", got, at)
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vdriver_test
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql"
+	"testing"
+
+	"github.com/louis77/valentina-go/vdriver"
+	"github.com/louis77/valentina-go/vdriver/vtest"
+)
+
+func startFakeTLS(t *testing.T) (*vtest.Server, *tls.Config) {
+	t.Helper()
+
+	srv := vtest.NewTLSServer()
+	t.Cleanup(srv.Close)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+	return srv, &tls.Config{RootCAs: pool}
+}
+
+func TestTLSRegisteredConfig(t *testing.T) {
+	srv, tlsConfig := startFakeTLS(t)
+
+	if err := vdriver.RegisterTLSConfig("vtest", tlsConfig); err != nil {
+		t.Fatalf("failed to register TLS config: %v", err)
+	}
+	defer vdriver.DeregisterTLSConfig("vtest")
+
+	db := openDSN(t, "valentina", srv.DSN("")+"?tls=vtest")
+	if err := db.Ping(); err != nil {
+		t.Fatalf("failed to ping: %v", err)
+	}
+}
+
+func TestTLSUntrusted(t *testing.T) {
+	srv, _ := startFakeTLS(t)
+
+	db := openDSN(t, "valentina", srv.DSN(""))
+	if err := db.Ping(); err == nil {
+		t.Fatal("ping should fail for an untrusted certificate")
+	}
+
+	db = openDSN(t, "valentina", srv.DSN("")+"?tls=skip-verify")
+	if err := db.Ping(); err != nil {
+		t.Fatalf("failed to ping with skip-verify: %v", err)
+	}
+
+	db = openDSN(t, "valentina", srv.DSN("")+"?tls=unknown")
+	if err := db.Ping(); err == nil {
+		t.Fatal("ping should fail for an unknown TLS config")
+	}
+}
+
+func TestTLSConnectorConfig(t *testing.T) {
+	srv, tlsConfig := startFakeTLS(t)
+
+	db := sql.OpenDB(vdriver.NewConnector(vdriver.VendorValentina, vdriver.Config{
+		User:      "sa",
+		Password:  "sa",
+		Host:      srv.Host(),
+		Port:      srv.Port(),
+		TLSConfig: tlsConfig,
+	}))
+	defer db.Close()
+
+	if err := db.Ping(); err != nil {
+		t.Fatalf("failed to ping: %v", err)
+	}
+}
+
+func TestRegisterTLSConfigReserved(t *testing.T) {
+	for _, name := range []string{"true", "false", "skip-verify"} {
+		if err := vdriver.RegisterTLSConfig(name, &tls.Config{}); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
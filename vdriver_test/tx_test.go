@@ -0,0 +1,111 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vdriver_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/louis77/valentina-go/vdriver"
+	"github.com/louis77/valentina-go/vdriver/vtest"
+)
+
+// queries returns the queries srv received, without the ones sent when connecting.
+func queries(srv *vtest.Server) []string {
+	var qs []string
+	for _, req := range srv.Requests() {
+		// Skip the session setup and the column type lookups of the driver
+		if strings.HasPrefix(req.Query, "SET PROPERTY ") || strings.HasPrefix(req.Query, "SHOW COLUMNS FROM ") {
+			continue
+		}
+		qs = append(qs, req.Query)
+	}
+	return qs
+}
+
+func TestTxCommit(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("BEGIN TRANSACTION", vtest.NoResult())
+	srv.Handle("INSERT INTO t VALUES (1)", vtest.Affected(1))
+	srv.Handle("COMMIT", vtest.NoResult())
+
+	db := openDSN(t, "vsqlite", srv.DSN(""))
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("failed to begin: %v", err)
+	}
+	if _, err := tx.Exec("INSERT INTO t VALUES (1)"); err != nil {
+		t.Fatalf("failed to exec: %v", err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+
+	got := queries(srv)
+	want := []string{"BEGIN TRANSACTION", "INSERT INTO t VALUES (1)", "COMMIT"}
+	if len(got) != len(want) {
+		t.Fatalf("got queries %q, want %q", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got queries %q, want %q", got, want)
+		}
+	}
+}
+
+func TestTxReadOnly(t *testing.T) {
+	srv := startFake(t)
+	srv.Handle("BEGIN TRANSACTION", vtest.NoResult())
+	srv.Handle("PRAGMA query_only = ON", vtest.NoResult())
+	srv.Handle("ROLLBACK", vtest.NoResult())
+	srv.Handle("PRAGMA query_only = OFF", vtest.NoResult())
+
+	db := openDSN(t, "vsqlite", srv.DSN(""))
+	tx, err := db.BeginTx(context.Background(), &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		t.Fatalf("failed to begin: %v", err)
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatalf("failed to rollback: %v", err)
+	}
+
+	if got := queries(srv); len(got) != 4 || got[3] != "PRAGMA query_only = OFF" {
+		t.Fatalf("unexpected queries: %q", got)
+	}
+}
+
+func TestTxUnsupportedOptions(t *testing.T) {
+	srv := startFake(t)
+
+	tests := []struct {
+		driverName string
+		opts       sql.TxOptions
+	}{
+		{"vsqlite", sql.TxOptions{Isolation: sql.LevelReadCommitted}},
+		{"vduckdb", sql.TxOptions{ReadOnly: true}},
+		{"vduckdb", sql.TxOptions{Isolation: sql.LevelLinearizable}},
+	}
+
+	for _, tt := range tests {
+		db := openDSN(t, tt.driverName, srv.DSN(""))
+		_, err := db.BeginTx(context.Background(), &tt.opts)
+		if !errors.Is(err, vdriver.ErrNotSupported) {
+			t.Errorf("%s %+v: expected ErrNotSupported, got %v", tt.driverName, tt.opts, err)
+		}
+	}
+}
+
+func TestTxValentina(t *testing.T) {
+	srv := startFake(t)
+
+	db := openFake(t, srv)
+	_, err := db.Begin()
+	if !errors.Is(err, vdriver.ErrTxNotImplemented) {
+		t.Fatalf("expected ErrTxNotImplemented, got %v", err)
+	}
+}
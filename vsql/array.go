@@ -0,0 +1,126 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vsql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Array is an ARRAY value with elements of type T, i.e. Array[int64], Array[float64],
+// Array[string] or Array[Time]. Other element types must be assignable from the driver's
+// values or implement sql.Scanner.
+//
+// The driver sends it as an ARRAY literal when used as a query parameter.
+type Array[T any] struct {
+	Elems []T
+
+	// Size is the declared size of the ARRAY field. If set, Scan and Value fail for arrays
+	// with a different number of elements.
+	Size int
+}
+
+// NewArray returns an array of the elements, its size is not checked.
+func NewArray[T any](elems ...T) Array[T] {
+	return Array[T]{Elems: elems}
+}
+
+func (a Array[T]) Value() (driver.Value, error) {
+	if a.Elems == nil {
+		return nil, nil
+	}
+	if err := a.checkSize(len(a.Elems)); err != nil {
+		return nil, err
+	}
+	return a.Elems, nil
+}
+
+func (a *Array[T]) Scan(value any) error {
+	if value == nil {
+		a.Elems = nil
+		return nil
+	}
+
+	values, ok := value.([]any)
+	if !ok {
+		return fmt.Errorf("unsupported Scan, storing driver.Value type %T into type *Array[%T]", value, *new(T))
+	}
+	if err := a.checkSize(len(values)); err != nil {
+		return err
+	}
+
+	elems := make([]T, len(values))
+	for i, v := range values {
+		if err := convertElem(&elems[i], v); err != nil {
+			return fmt.Errorf("array element %d: %w", i, err)
+		}
+	}
+	a.Elems = elems
+	return nil
+}
+
+func (a Array[T]) checkSize(n int) error {
+	if a.Size > 0 && n != a.Size {
+		return fmt.Errorf("array has %d elements, expected %d", n, a.Size)
+	}
+	return nil
+}
+
+// convertElem stores an element decoded by the driver into dest.
+func convertElem[T any](dest *T, v any) error {
+	if scanner, ok := any(dest).(sql.Scanner); ok {
+		return scanner.Scan(v)
+	}
+	if v == nil {
+		return fmt.Errorf("cannot store NULL into type %T", *dest)
+	}
+
+	switch d := any(dest).(type) {
+	case *int64:
+		switch v := v.(type) {
+		case int64:
+			*d = v
+			return nil
+		case float64:
+			if v == math.Trunc(v) && v >= math.MinInt64 && v < math.MaxInt64 {
+				*d = int64(v)
+				return nil
+			}
+		case string:
+			// Integers that don't fit into an int64 are decoded as strings
+			return fmt.Errorf("value %s out of range for type int64", v)
+		}
+	case *float64:
+		switch v := v.(type) {
+		case float64:
+			*d = v
+			return nil
+		case int64:
+			*d = float64(v)
+			return nil
+		case string:
+			f, err := strconv.ParseFloat(v, 64)
+			if err == nil {
+				*d = f
+				return nil
+			}
+		}
+	case *string:
+		if v, ok := v.(string); ok {
+			*d = v
+			return nil
+		}
+	default:
+		if v, ok := v.(T); ok {
+			*dest = v
+			return nil
+		}
+	}
+
+	return fmt.Errorf("cannot store %T into type %T", v, *dest)
+}
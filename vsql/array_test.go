@@ -0,0 +1,84 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vsql
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestArrayScan(t *testing.T) {
+	var ints Array[int64]
+	if err := ints.Scan([]any{int64(1), int64(2), float64(3)}); err != nil {
+		t.Fatalf("failed to scan: %v", err)
+	}
+	if !reflect.DeepEqual(ints.Elems, []int64{1, 2, 3}) {
+		t.Errorf("elements are %v", ints.Elems)
+	}
+
+	var floats Array[float64]
+	if err := floats.Scan([]any{int64(1), 2.5}); err != nil {
+		t.Fatalf("failed to scan: %v", err)
+	}
+	if !reflect.DeepEqual(floats.Elems, []float64{1, 2.5}) {
+		t.Errorf("elements are %v", floats.Elems)
+	}
+
+	var strs Array[string]
+	if err := strs.Scan([]any{"a", "b"}); err != nil {
+		t.Fatalf("failed to scan: %v", err)
+	}
+	if !reflect.DeepEqual(strs.Elems, []string{"a", "b"}) {
+		t.Errorf("elements are %v", strs.Elems)
+	}
+
+	var times Array[Time]
+	if err := times.Scan([]any{"2025-01-02 17:56:39:400"}); err != nil {
+		t.Fatalf("failed to scan: %v", err)
+	}
+	if want := time.Date(2025, 1, 2, 17, 56, 39, 400_000_000, time.UTC); !times.Elems[0].Equal(want) {
+		t.Errorf("elements are %v", times.Elems)
+	}
+
+	if err := strs.Scan(nil); err != nil || strs.Elems != nil {
+		t.Errorf("expected NULL array, got %v, %v", strs.Elems, err)
+	}
+}
+
+func TestArrayScanInvalid(t *testing.T) {
+	ints := Array[int64]{Size: 2}
+	tests := []any{
+		[]any{int64(1)},                         // Wrong size
+		[]any{int64(1), "x"},                    // Wrong element type
+		[]any{int64(1), 1.5},                    // Not an integer
+		[]any{int64(1), nil},                    // NULL element
+		[]any{int64(1), "18446744073709551615"}, // Out of range
+		"{1,2}",
+	}
+
+	for _, v := range tests {
+		if err := ints.Scan(v); err == nil {
+			t.Errorf("%#v: expected an error", v)
+		}
+	}
+}
+
+func TestArrayValue(t *testing.T) {
+	v, err := NewArray[int64](1, 2).Value()
+	if err != nil {
+		t.Fatalf("failed to get value: %v", err)
+	}
+	if !reflect.DeepEqual(v, []int64{1, 2}) {
+		t.Errorf("value is %#v", v)
+	}
+
+	if _, err := (Array[int64]{Elems: []int64{1}, Size: 3}).Value(); err == nil {
+		t.Errorf("expected a size error")
+	}
+	if v, _ := (Array[string]{}).Value(); v != nil {
+		t.Errorf("value is %#v, expected nil", v)
+	}
+}
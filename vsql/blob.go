@@ -0,0 +1,86 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vsql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+)
+
+// Querier is implemented by *sql.DB, *sql.Conn and *sql.Tx.
+type Querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// Execer is implemented by *sql.DB, *sql.Conn and *sql.Tx.
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// ReadBlob writes the value of a BLOB, PICTURE or TEXT field of the record with the given
+// RecID to w and returns the number of bytes written. The table and field names are
+// inserted into the query as they are, quote them if necessary.
+// It returns sql.ErrNoRows if there is no such record.
+//
+// ReadBlob doesn't stream: the REST API returns the value as a single hex encoded JSON
+// string, which is held in memory while it is decoded. It fails if the driver can't look
+// up the type of the field, because it couldn't tell a hex encoded BLOB from a TEXT value.
+func ReadBlob(ctx context.Context, db Querier, w io.Writer, table, field string, recID int64) (int64, error) {
+	rows, err := db.QueryContext(ctx, "SELECT "+field+" FROM "+table+" WHERE RecID = :1", recID)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return 0, err
+		}
+		return 0, sql.ErrNoRows
+	}
+
+	// Without the type, the driver returns BLOBs as their hex string
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return 0, err
+	}
+	if types[0].DatabaseTypeName() == "" {
+		return 0, fmt.Errorf("cannot read %s of %s: unknown field type", field, table)
+	}
+
+	// RawBytes refers to the driver's value, so it isn't copied
+	var value sql.RawBytes
+	if err := rows.Scan(&value); err != nil {
+		return 0, err
+	}
+	n, err := w.Write(value)
+	if err != nil {
+		return int64(n), err
+	}
+
+	return int64(n), rows.Close()
+}
+
+// WriteBlob stores the content of r into a BLOB or PICTURE field of the record with the
+// given RecID. The content is streamed into the request. The table and field names are
+// inserted into the query as they are.
+// It returns sql.ErrNoRows if there is no such record.
+func WriteBlob(ctx context.Context, db Execer, table, field string, recID int64, r io.Reader) error {
+	result, err := db.ExecContext(ctx, "UPDATE "+table+" SET "+field+" = :1 WHERE RecID = :2", r, recID)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vsql
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/louis77/valentina-go/vdriver/vtest"
+)
+
+func TestBlobs(t *testing.T) {
+	srv := vtest.NewServer()
+	t.Cleanup(srv.Close)
+
+	srv.Handle("SHOW COLUMNS FROM docs",
+		vtest.Table([]string{"fld_name", "fld_type_str"}, []any{"data", "BLOB"}))
+	srv.Handle("SELECT data FROM docs WHERE RecID = :1",
+		vtest.Table([]string{"data"}, []any{"48656c6c6f00ff"}),
+		vtest.Table([]string{"data"}),
+	)
+	srv.Handle("SELECT upper(data) FROM docs WHERE RecID = :1",
+		vtest.Table([]string{"upper(data)"}, []any{"48656c6c6f00ff"}))
+	srv.HandleFunc(func(req vtest.Request) (vtest.Response, bool) {
+		if strings.HasPrefix(req.Query, "UPDATE docs") {
+			if req.Params[1].(interface{ String() string }).String() == "1" {
+				return vtest.Affected(1), true
+			}
+			return vtest.Affected(0), true
+		}
+		return vtest.Response{}, false
+	})
+
+	db, err := sql.Open("valentina", srv.DSN(""))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	n, err := ReadBlob(ctx, db, &buf, "docs", "data", 1)
+	if err != nil {
+		t.Fatalf("failed to read blob: %v", err)
+	}
+	if want := []byte("Hello\x00\xff"); n != int64(len(want)) || !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("read %d bytes %q, expected %q", n, buf.Bytes(), want)
+	}
+
+	if _, err := ReadBlob(ctx, db, &buf, "docs", "data", 2); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows, got %v", err)
+	}
+
+	// The hex string isn't written as the value if the field's type is unknown
+	buf.Reset()
+	if _, err := ReadBlob(ctx, db, &buf, "docs", "upper(data)", 1); err == nil || !strings.Contains(err.Error(), "unknown field type") {
+		t.Errorf("expected an unknown field type error, got %v", err)
+	}
+	if buf.Len() > 0 {
+		t.Errorf("wrote %q for a field of unknown type", buf.Bytes())
+	}
+
+	if err := WriteBlob(ctx, db, "docs", "data", 1, bytes.NewReader([]byte("Hi\x00"))); err != nil {
+		t.Fatalf("failed to write blob: %v", err)
+	}
+	reqs := srv.Requests()
+	if got := reqs[len(reqs)-1]; got.Query != "UPDATE docs SET data = :1 WHERE RecID = :2" || got.Params[0] != "486900" {
+		t.Errorf("unexpected request: %+v", got)
+	}
+
+	if err := WriteBlob(ctx, db, "docs", "data", 2, strings.NewReader("x")); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("expected sql.ErrNoRows, got %v", err)
+	}
+}
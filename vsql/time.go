@@ -6,26 +6,90 @@ package vsql
 
 import (
 	"database/sql/driver"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 )
 
 const (
-	vTimeFormat = "2006-01-02 15:04:05.000" // Yes, we can't use : as the MS separator, see Scan()
+	clockLayout      = "15:04:05.999999999"
+	clockValueLayout = "15:04:05.000"
+	isoDateLayout    = "2006-01-02"
 )
 
+// parseDateTime parses a DATETIME value like "2025-01-02 17:56:39:400" in the default
+// DateTimeFormat kYMD. The time part is optional.
+func parseDateTime(s string) (time.Time, error) {
+	date, clock, found := strings.Cut(s, " ")
+	if !found {
+		return parseDate(s)
+	}
+
+	t, err := time.Parse(isoDateLayout+" "+clockLayout, date+" "+normalizeClock(clock))
+	if err != nil {
+		return time.Time{}, dateError("DATETIME", s, date)
+	}
+	return t, nil
+}
+
+// parseDate parses a DATE value like "2025-01-02".
+func parseDate(s string) (time.Time, error) {
+	t, err := time.Parse(isoDateLayout, s)
+	if err != nil {
+		return time.Time{}, dateError("DATE", s, s)
+	}
+	return t, nil
+}
+
+// dateError returns the error for an invalid value. Dates that end with the year are in the
+// DateTimeFormat kDMY or kMDY, which can't be told apart without the session.
+func dateError(typ, s, date string) error {
+	if len(date) == len("02-01-2006") && date[2] == '-' && date[5] == '-' {
+		return fmt.Errorf("invalid %s value %q: only kYMD dates can be scanned, use parseTime=true for other date formats", typ, s)
+	}
+	return fmt.Errorf("invalid %s value %q", typ, s)
+}
+
+// parseClock parses a TIME value like "17:56:39:400".
+func parseClock(s string) (time.Time, error) {
+	t, err := time.Parse(clockLayout, normalizeClock(s))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid TIME value %q", s)
+	}
+	return t, nil
+}
+
+// normalizeClock replaces the colon Valentina puts before the milliseconds with a dot.
+func normalizeClock(s string) string {
+	if strings.Count(s, ":") == 3 {
+		i := strings.LastIndexByte(s, ':')
+		return s[:i] + "." + s[i+1:]
+	}
+	return s
+}
+
+// scanString returns the string of a scanned value, or ok false if it isn't one.
+func scanString(value any) (s string, ok bool) {
+	switch value := value.(type) {
+	case string:
+		return value, true
+	case []byte:
+		return string(value), true
+	}
+	return "", false
+}
+
+// Time is a DATETIME value. Scan only sees the value, not the session, so strings are scanned
+// in the default DateTimeFormat kYMD and in UTC. With another dateFormat or a loc, use the
+// parseTime option, so the driver parses the values in the format and location of the session.
+// The driver formats values in the format of the session.
 type Time struct {
 	time.Time
 }
 
 func (t Time) Value() (driver.Value, error) {
-	// This is the default date/time format in Valentina.
-	// However, this can be changed in the database.
-	// TODO Check if we need to do anything about it.
-
-	value := t.Format(vTimeFormat)
-	value = value[:19] + string(".") + value[20:]
-	return value, nil
+	return t.Time, nil
 }
 
 func (t *Time) Scan(value any) error {
@@ -34,23 +98,219 @@ func (t *Time) Scan(value any) error {
 		return nil
 	}
 	// Valentina returns the time in the format "2006-01-02 15:04:05:000"
-	// We can't use : as the MS separator, so we have to parse it manually
+	// We can't use : as the MS separator, so parseDateTime replaces it
 
-	switch value := value.(type) {
-	case string:
-		// Replace the MS separator : with a .
-		value = value[:19] + string(".") + value[20:]
-
-		tt, err := time.Parse(vTimeFormat, value[:len(vTimeFormat)])
+	if s, ok := scanString(value); ok {
+		tt, err := parseDateTime(s)
 		if err != nil {
 			return err
 		}
 		*t = Time{tt}
 		return nil
-	case time.Time:
+	}
+	if value, ok := value.(time.Time); ok {
 		*t = Time{value}
 		return nil
-	default:
-		return fmt.Errorf("unsupported Scan, storing driver.Value type %T into type *Time", value)
 	}
+	return fmt.Errorf("unsupported Scan, storing driver.Value type %T into type *Time", value)
+}
+
+// Date is a DATE value. The time of day and the location are ignored, the driver sends the
+// date in the format of the session. Like Time, strings are scanned in the DateTimeFormat kYMD.
+type Date struct {
+	time.Time
+}
+
+// Value returns the date in ISO format, the driver uses DateValue instead.
+func (d Date) Value() (driver.Value, error) {
+	return d.String(), nil
+}
+
+// DateValue returns the year, month and day of the date. The driver sends them in the format
+// of the session, without converting them into the loc of the connection.
+func (d Date) DateValue() (year int, month time.Month, day int) {
+	return d.Date()
+}
+
+func (d *Date) Scan(value any) error {
+	if value == nil {
+		*d = Date{}
+		return nil
+	}
+
+	if s, ok := scanString(value); ok {
+		t, err := parseDate(s)
+		if err != nil {
+			return err
+		}
+		*d = Date{t}
+		return nil
+	}
+	if value, ok := value.(time.Time); ok {
+		*d = Date{time.Date(value.Year(), value.Month(), value.Day(), 0, 0, 0, 0, value.Location())}
+		return nil
+	}
+	return fmt.Errorf("unsupported Scan, storing driver.Value type %T into type *Date", value)
+}
+
+// String returns the date in ISO format, i.e. 2025-01-02.
+func (d Date) String() string {
+	return d.Format(isoDateLayout)
+}
+
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
+
+// UnmarshalText parses a date in ISO format, the time.Time UnmarshalText would expect RFC 3339.
+func (d *Date) UnmarshalText(text []byte) error {
+	t, err := parseDate(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Date{t}
+	return nil
+}
+
+func (d *Date) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid DATE value %s", data)
+	}
+	return d.UnmarshalText([]byte(s))
+}
+
+// TimeOfDay is a TIME value.
+type TimeOfDay struct {
+	Hour       int
+	Minute     int
+	Second     int
+	Nanosecond int
+}
+
+func (t TimeOfDay) Value() (driver.Value, error) {
+	return t.String(), nil
+}
+
+func (t *TimeOfDay) Scan(value any) error {
+	if value == nil {
+		*t = TimeOfDay{}
+		return nil
+	}
+
+	var tt time.Time
+	if s, ok := scanString(value); ok {
+		var err error
+		if tt, err = parseClock(s); err != nil {
+			return err
+		}
+	} else if value, ok := value.(time.Time); ok {
+		tt = value
+	} else {
+		return fmt.Errorf("unsupported Scan, storing driver.Value type %T into type *TimeOfDay", value)
+	}
+
+	*t = TimeOfDay{Hour: tt.Hour(), Minute: tt.Minute(), Second: tt.Second(), Nanosecond: tt.Nanosecond()}
+	return nil
+}
+
+// String returns the time with milliseconds, i.e. 17:56:39.400.
+func (t TimeOfDay) String() string {
+	return time.Date(0, 1, 1, t.Hour, t.Minute, t.Second, t.Nanosecond, time.UTC).Format(clockValueLayout)
+}
+
+func (t TimeOfDay) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t TimeOfDay) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+// UnmarshalText parses a time like 17:56:39.400 or 17:56:39:400.
+func (t *TimeOfDay) UnmarshalText(text []byte) error {
+	return t.Scan(string(text))
+}
+
+func (t *TimeOfDay) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("invalid TIME value %s", data)
+	}
+	return t.Scan(s)
+}
+
+// NullTime is a DATETIME value that may be NULL.
+type NullTime struct {
+	Time  time.Time
+	Valid bool // Valid is true if Time is not NULL
+}
+
+func (nt NullTime) Value() (driver.Value, error) {
+	if !nt.Valid {
+		return nil, nil
+	}
+	return Time{nt.Time}.Value()
+}
+
+func (nt *NullTime) Scan(value any) error {
+	if value == nil {
+		*nt = NullTime{}
+		return nil
+	}
+
+	var t Time
+	if err := t.Scan(value); err != nil {
+		return err
+	}
+	*nt = NullTime{Time: t.Time, Valid: true}
+	return nil
+}
+
+// MarshalText returns the time in RFC 3339 format, or an empty text if it is NULL.
+func (nt NullTime) MarshalText() ([]byte, error) {
+	if !nt.Valid {
+		return []byte{}, nil
+	}
+	return nt.Time.MarshalText()
+}
+
+// MarshalJSON returns the time in RFC 3339 format, or null if it is NULL.
+func (nt NullTime) MarshalJSON() ([]byte, error) {
+	if !nt.Valid {
+		return []byte("null"), nil
+	}
+	return nt.Time.MarshalJSON()
+}
+
+// UnmarshalText parses a time in RFC 3339 format, an empty text is NULL.
+func (nt *NullTime) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*nt = NullTime{}
+		return nil
+	}
+	var t time.Time
+	if err := t.UnmarshalText(text); err != nil {
+		return err
+	}
+	*nt = NullTime{Time: t, Valid: true}
+	return nil
+}
+
+// UnmarshalJSON parses a time in RFC 3339 format or null.
+func (nt *NullTime) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*nt = NullTime{}
+		return nil
+	}
+	var t time.Time
+	if err := t.UnmarshalJSON(data); err != nil {
+		return err
+	}
+	*nt = NullTime{Time: t, Valid: true}
+	return nil
 }
@@ -0,0 +1,174 @@
+// Copyright 2025 Louis Brauer. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package vsql
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScanInvalidTimes(t *testing.T) {
+	values := []any{"", "2025", "2025-01-02 17", "17:56", "2025-13-02", 42}
+
+	for _, v := range values {
+		var tm Time
+		var d Date
+		var tod TimeOfDay
+		var nt NullTime
+		if err := tm.Scan(v); err == nil {
+			t.Errorf("Time: expected an error for %#v", v)
+		}
+		if err := d.Scan(v); err == nil {
+			t.Errorf("Date: expected an error for %#v", v)
+		}
+		if err := tod.Scan(v); err == nil {
+			t.Errorf("TimeOfDay: expected an error for %#v", v)
+		}
+		if err := nt.Scan(v); err == nil {
+			t.Errorf("NullTime: expected an error for %#v", v)
+		}
+	}
+}
+
+func TestScanOtherDateFormat(t *testing.T) {
+	// kDMY and kMDY dates can't be told apart without the session
+	var d Date
+	err := d.Scan("02-01-2025")
+	if err == nil || !strings.Contains(err.Error(), "parseTime") {
+		t.Errorf("expected an error pointing to parseTime, got %v", err)
+	}
+}
+
+func TestDate(t *testing.T) {
+	var d Date
+	if err := d.Scan("2025-01-02"); err != nil {
+		t.Fatalf("failed to scan: %v", err)
+	}
+	if !d.Equal(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("date is %v", d.Time)
+	}
+
+	if v, _ := d.Value(); v != "2025-01-02" {
+		t.Errorf("value is %v", v)
+	}
+	if b, _ := json.Marshal(d); string(b) != `"2025-01-02"` {
+		t.Errorf("JSON is %s", b)
+	}
+}
+
+func TestTimeValue(t *testing.T) {
+	// The driver formats time.Time in the DateTimeFormat of the session
+	tm := Time{time.Date(2025, 1, 2, 17, 56, 39, 400_000_000, time.UTC)}
+	if v, _ := tm.Value(); v != tm.Time {
+		t.Errorf("value is %v, expected %v", v, tm.Time)
+	}
+
+	// Dates are no points in time, the location doesn't shift them
+	d := Date{time.Date(2025, 1, 2, 23, 56, 39, 0, time.FixedZone("EST", -5*3600))}
+	if v, _ := d.Value(); v != "2025-01-02" {
+		t.Errorf("value is %v, expected the date", v)
+	}
+	if y, m, day := d.DateValue(); y != 2025 || m != 1 || day != 2 {
+		t.Errorf("date value is %d-%d-%d", y, m, day)
+	}
+
+	if v, _ := (NullTime{Time: tm.Time, Valid: true}).Value(); v != tm.Time {
+		t.Errorf("value is %v, expected %v", v, tm.Time)
+	}
+}
+
+func TestTimeOfDay(t *testing.T) {
+	var tod TimeOfDay
+	if err := tod.Scan("17:56:39:400"); err != nil {
+		t.Fatalf("failed to scan: %v", err)
+	}
+	if want := (TimeOfDay{17, 56, 39, 400_000_000}); tod != want {
+		t.Errorf("time is %+v, expected %+v", tod, want)
+	}
+
+	if v, _ := tod.Value(); v != "17:56:39.400" {
+		t.Errorf("value is %v", v)
+	}
+	if b, _ := json.Marshal(tod); string(b) != `"17:56:39.400"` {
+		t.Errorf("JSON is %s", b)
+	}
+}
+
+func TestNullTime(t *testing.T) {
+	var nt NullTime
+	if err := nt.Scan(nil); err != nil || nt.Valid {
+		t.Fatalf("expected a NULL time, got %+v, %v", nt, err)
+	}
+	if v, _ := nt.Value(); v != nil {
+		t.Errorf("value is %v, expected nil", v)
+	}
+	if b, _ := json.Marshal(nt); string(b) != "null" {
+		t.Errorf("JSON is %s", b)
+	}
+
+	if err := nt.Scan("2025-01-02 17:56:39:400"); err != nil || !nt.Valid {
+		t.Fatalf("failed to scan: %+v, %v", nt, err)
+	}
+	if b, _ := json.Marshal(nt); string(b) != `"2025-01-02T17:56:39.4Z"` {
+		t.Errorf("JSON is %s", b)
+	}
+	if b, _ := nt.MarshalText(); string(b) != "2025-01-02T17:56:39.4Z" {
+		t.Errorf("text is %s", b)
+	}
+}
+
+func TestTimesRoundTrip(t *testing.T) {
+	type record struct {
+		Born    Date
+		Wakeup  TimeOfDay
+		Deleted NullTime
+		Created NullTime
+	}
+	in := record{
+		Born:    Date{time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)},
+		Wakeup:  TimeOfDay{17, 56, 39, 400_000_000},
+		Created: NullTime{Time: time.Date(2025, 1, 2, 17, 56, 39, 400_000_000, time.UTC), Valid: true},
+	}
+
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	var out record
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("failed to unmarshal %s: %v", b, err)
+	}
+	if !out.Born.Equal(in.Born.Time) || out.Wakeup != in.Wakeup || out.Deleted.Valid || !out.Created.Valid || !out.Created.Time.Equal(in.Created.Time) {
+		t.Errorf("unmarshaled %+v, expected %+v", out, in)
+	}
+
+	for _, v := range []interface {
+		MarshalText() ([]byte, error)
+		UnmarshalText([]byte) error
+	}{&in.Born, &in.Wakeup, &in.Deleted, &in.Created} {
+		text, err := v.MarshalText()
+		if err != nil {
+			t.Fatalf("%T: failed to marshal: %v", v, err)
+		}
+		if err := v.UnmarshalText(text); err != nil {
+			t.Errorf("%T: failed to unmarshal %q: %v", v, text, err)
+		}
+	}
+
+	// The strict parsers reject other formats
+	var d Date
+	var tod TimeOfDay
+	if err := json.Unmarshal([]byte(`"2025-01-02T00:00:00Z"`), &d); err == nil {
+		t.Errorf("Date: expected an error for an RFC 3339 time")
+	}
+	if err := json.Unmarshal([]byte(`"17:56"`), &tod); err == nil {
+		t.Errorf("TimeOfDay: expected an error for an incomplete time")
+	}
+	if err := json.Unmarshal([]byte(`42`), &d); err == nil {
+		t.Errorf("Date: expected an error for a number")
+	}
+}